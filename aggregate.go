@@ -0,0 +1,119 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+var aggregates = []string{"count", "sum", "avg", "min", "max"}
+
+//Count return count(field) with the alias to use in Select.SetFields or as the filter field
+//of Select.Having, field can be * to count the rows. The alias is not rendered when it is empty.
+//	SetFields("dept", Count("*", "n"), Avg("salary", "avgsalary"))
+func Count(field, alias string) string {
+	return aggregate("count", field, alias)
+}
+
+//Sum return sum(field) with the alias, see Count.
+func Sum(field, alias string) string {
+	return aggregate("sum", field, alias)
+}
+
+//Avg return avg(field) with the alias, see Count.
+func Avg(field, alias string) string {
+	return aggregate("avg", field, alias)
+}
+
+//Min return min(field) with the alias, see Count.
+func Min(field, alias string) string {
+	return aggregate("min", field, alias)
+}
+
+//Max return max(field) with the alias, see Count.
+func Max(field, alias string) string {
+	return aggregate("max", field, alias)
+}
+
+func aggregate(fn, field, alias string) string {
+	query := fn + "(" + strings.ToLower(field) + ")"
+	if alias != "" {
+		query += " AS " + strings.ToLower(alias)
+	}
+	return query
+}
+
+//splitAggregate split fn(arg) into the aggregate function and the argument.
+func splitAggregate(field string) (fn, arg string, ok bool) {
+	i := strings.Index(field, "(")
+	if i < 0 || !strings.HasSuffix(field, ")") {
+		return "", "", false
+	}
+	fn = strings.ToLower(field[:i])
+	for _, v := range aggregates {
+		if v == fn {
+			return fn, strings.TrimSpace(field[i+1 : len(field)-1]), true
+		}
+	}
+	return "", "", false
+}
+
+//quoteField quote the field which can be an aggregate function of the field.
+func quoteField(d Dialect, field string) string {
+	if fn, arg, ok := splitAggregate(field); ok {
+		if arg != "*" {
+			arg = d.Quote(arg)
+		}
+		return fn + "(" + arg + ")"
+	}
+	return d.Quote(field)
+}
+
+//GroupBy set the fields to group the rows, when OrderBy is not called the query
+//is ordered by the group by fields instead of the primary keys.
+func (s *Select) GroupBy(fieldName ...string) *Select {
+	for _, field := range fieldName {
+		s.groupBy = append(s.groupBy, strings.ToLower(field))
+	}
+	return s
+}
+
+//Having set the conditions for the groups, the field of the condition can be
+//the aggregate function:
+//	Having(Filter(Count("*", ""), ">", 5))
+func (s *Select) Having(conds ...Condition) *Select {
+	for _, cond := range conds {
+		s.having = append(s.having, asFilter(cond))
+	}
+	return s
+}
+
+//groupQuery return the GROUP BY and HAVING clause.
+func (s *Select) groupQuery(starting int) (query string, args []interface{}, next int) {
+	if len(s.groupBy) == 0 {
+		return "", nil, starting
+	}
+	query = " GROUP BY " + quoteFields(s.d, s.groupBy)
+	if len(s.having) == 0 {
+		return query, nil, starting
+	}
+	having, args, next := filterQuery(s.d, s.exprFilters(s.having), starting)
+	query += strings.Replace(having, " WHERE ", " HAVING ", 1)
+	return query, args, next
+}
+
+func (s *Select) groupError() error {
+	for _, field := range s.groupBy {
+		if !s.fieldExist(field) {
+			return fmt.Errorf("group by field %s doesn't exist", field)
+		}
+	}
+	if len(s.having) != 0 && len(s.groupBy) == 0 {
+		return fmt.Errorf("having need group by")
+	}
+	for _, f := range s.having {
+		if err := s.checkFilter(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
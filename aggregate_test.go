@@ -0,0 +1,104 @@
+package qb
+
+import "testing"
+
+type aggEmp struct {
+	ID     string `pk:"1"`
+	Dept   string
+	Salary int
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{Count("*", "n"), "count(*) AS n"},
+		{Count("ID", ""), "count(id)"},
+		{Sum("salary", "total"), "sum(salary) AS total"},
+		{Avg("salary", "AvgSalary"), "avg(salary) AS avgsalary"},
+		{Min("salary", "lo"), "min(salary) AS lo"},
+		{Max("salary", "hi"), "max(salary) AS hi"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got: %s want %s", tt.got, tt.want)
+		}
+	}
+}
+
+func TestGroupByQuery(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetFields("dept", Count("*", "n"), Avg("salary", "avgsalary")).
+		SetFilter("salary", ">", 10).
+		GroupBy("dept").
+		Having(Filter(Count("*", ""), ">", 5), Filter(Max("salary", ""), "<", 100))
+	wantQ := "SELECT dept,count(*) AS n,avg(salary) AS avgsalary FROM aggemp WHERE salary > $1" +
+		" GROUP BY dept HAVING count(*) > $2 AND max(salary) < $3 ORDER BY dept"
+	testQuery(t, b, wantQ, []interface{}{10, 5, 100})
+	testError(t, b, false)
+
+	b.OrderBy(Desc("n")).SetLimit(3)
+	wantQ = "SELECT dept,count(*) AS n,avg(salary) AS avgsalary FROM aggemp WHERE salary > $1" +
+		" GROUP BY dept HAVING count(*) > $2 AND max(salary) < $3 ORDER BY n DESC LIMIT 3"
+	testQuery(t, b, wantQ, []interface{}{10, 5, 100})
+	testError(t, b, false)
+
+	b.Reset()
+	if len(b.groupBy) != 0 || len(b.having) != 0 {
+		t.Errorf("got group by %v having %v after reset want empty", b.groupBy, b.having)
+	}
+}
+
+func TestGroupByMySQL(t *testing.T) {
+	ti, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	b := NewMySQLSelect(ti, false)
+	b.SetFields("dept", Sum("salary", "total")).GroupBy("dept").Having(Filter(Sum("salary", ""), ">=", 50))
+	wantQ := "SELECT `dept`,sum(`salary`) AS `total` FROM `emp` GROUP BY `dept` HAVING sum(`salary`) >= ? ORDER BY `dept`"
+	testQuery(t, b, wantQ, []interface{}{50})
+}
+
+func TestHavingWithoutWhere(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetFields("dept", Count("*", "n")).GroupBy("dept").Having(Filter(Count("*", ""), ">", 5))
+	wantQ := "SELECT dept,count(*) AS n FROM aggemp GROUP BY dept HAVING count(*) > $1 ORDER BY dept"
+	testQuery(t, b, wantQ, []interface{}{5})
+
+	ti, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	b = NewSQLServerSelect(ti, false)
+	b.SetFields("dept", Count("*", "n")).GroupBy("dept").Having(Filter(Count("*", ""), ">", 5))
+	wantQ = "SELECT [dept],count(*) AS [n] FROM [emp] GROUP BY [dept] HAVING count(*) > @p1 ORDER BY [dept]"
+	testQuery(t, b, wantQ, []interface{}{5})
+}
+
+func TestGroupByError(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetFields(Sum("notexist", "n")).GroupBy("dept")
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields(Sum("*", "n")).GroupBy("dept")
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields("dept").GroupBy("notexist")
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields("dept", Count("*", "n")).Having(Filter(Count("*", ""), ">", 1))
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields("dept").GroupBy("dept").Having(Filter(Avg("notexist", ""), ">", 1))
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields("dept").GroupBy("dept").OrderBy("n")
+	testError(t, b, true)
+}
@@ -0,0 +1,59 @@
+package qb
+
+import "fmt"
+
+const (
+	opUnion     = "UNION"
+	opUnionAll  = "UNION ALL"
+	opIntersect = "INTERSECT"
+	opExcept    = "EXCEPT"
+)
+
+type compound struct {
+	op string
+	s  *Select
+}
+
+//Union combine the rows of s and other removing the duplicate rows,
+//other must select the same number of fields as s.
+//ORDER BY, LIMIT and OFFSET of s is applied to the combined rows and the order fields
+//refer to the fields of s, while ORDER BY and LIMIT of other is ignored.
+//The compound query is evaluated from left to right, except the database that
+//give INTERSECT higher precedence.
+//	active.UnionAll(archived).OrderBy(Desc("joindate")).SetLimit(10)
+func (s *Select) Union(other *Select) *Select {
+	return s.combine(opUnion, other)
+}
+
+//UnionAll combine the rows of s and other including the duplicate rows, see Union.
+func (s *Select) UnionAll(other *Select) *Select {
+	return s.combine(opUnionAll, other)
+}
+
+//Intersect return the rows of s that is also returned by other, see Union.
+func (s *Select) Intersect(other *Select) *Select {
+	return s.combine(opIntersect, other)
+}
+
+//Except return the rows of s that is not returned by other, see Union.
+func (s *Select) Except(other *Select) *Select {
+	return s.combine(opExcept, other)
+}
+
+func (s *Select) combine(op string, other *Select) *Select {
+	s.compound = append(s.compound, compound{op: op, s: other})
+	return s
+}
+
+func (s *Select) compoundError() error {
+	n := len(s.scanFields())
+	for _, c := range s.compound {
+		if err := c.s.Error(); err != nil {
+			return fmt.Errorf("%s: %v", c.op, err)
+		}
+		if len(c.s.scanFields()) != n {
+			return fmt.Errorf("%s: the number of fields doesn't match", c.op)
+		}
+	}
+	return nil
+}
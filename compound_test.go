@@ -0,0 +1,74 @@
+package qb
+
+import "testing"
+
+func newCompoundTables(t *testing.T) (active, archived Table) {
+	active, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	archived, err = NewTable("archived", aggEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	return active, archived
+}
+
+func TestCompoundQuery(t *testing.T) {
+	active, archived := newCompoundTables(t)
+	b := NewPQSelect(active, false).SetFields("id", "salary").SetFilter("dept", "=", "it")
+	b.UnionAll(NewPQSelect(archived, false).SetFields("id", "salary").SetFilter("dept", "=", "hr").OrderBy("salary"))
+	b.OrderBy(Desc("salary")).SetLimit(5).SetOffset(10)
+	wantQ := "SELECT id,salary FROM emp WHERE dept = $1 UNION ALL SELECT id,salary FROM archived WHERE dept = $2" +
+		" ORDER BY salary DESC,id LIMIT 5 OFFSET 10"
+	testQuery(t, b, wantQ, []interface{}{"it", "hr"})
+	testError(t, b, false)
+
+	wantQ = "SELECT * FROM (SELECT id,salary FROM emp WHERE dept = $1 UNION ALL SELECT id,salary FROM archived" +
+		" WHERE dept = $2 ORDER BY salary,id DESC LIMIT 5) AS xxlast ORDER BY salary DESC,id"
+	if got, _ := b.lastQuery(); got != wantQ {
+		t.Errorf("got query: %s \n             want %s", got, wantQ)
+	}
+
+	b.Reset()
+	b.SetFields("id").SetFilter("salary", ">", 5)
+	b.Union(NewPQSelect(archived, false).SetFields("id").SetFilter("salary", "<", 3)).
+		Except(NewPQSelect(archived, false).SetFields("id").SetFilter("dept", "IN", []string{"a", "b"})).
+		Intersect(NewPQSelect(active, false).SetFields("id"))
+	wantQ = "SELECT id FROM emp WHERE salary > $1 UNION SELECT id FROM archived WHERE salary < $2" +
+		" EXCEPT SELECT id FROM archived WHERE dept IN ($3,$4) INTERSECT SELECT id FROM emp ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{5, 3, "a", "b"})
+	testError(t, b, false)
+
+	//the placeholder of the next select is numbered after the select without filter.
+	b.Reset()
+	b.SetFields("id").Union(NewPQSelect(archived, false).SetFields("id").SetFilter("salary", ">", 1))
+	wantQ = "SELECT id FROM emp UNION SELECT id FROM archived WHERE salary > $1 ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{1})
+}
+
+func TestCompoundSQLServer(t *testing.T) {
+	active, archived := newCompoundTables(t)
+	b := NewSQLServerSelect(active, false).SetFields("id")
+	b.Union(NewSQLServerSelect(archived, false).SetFields("id")).SetLimit(3)
+	wantQ := "SELECT [id] FROM [emp] UNION SELECT [id] FROM [archived] ORDER BY [id] OFFSET 0 ROWS FETCH NEXT 3 ROWS ONLY"
+	testQuery(t, b, wantQ, nil)
+	b.SetOffset(3)
+	wantQ = "SELECT [id] FROM [emp] UNION SELECT [id] FROM [archived] ORDER BY [id] OFFSET 3 ROWS FETCH NEXT 3 ROWS ONLY"
+	testQuery(t, b, wantQ, nil)
+}
+
+func TestCompoundError(t *testing.T) {
+	active, archived := newCompoundTables(t)
+	b := NewPQSelect(active, false).SetFields("id", "salary")
+	b.Union(NewPQSelect(archived, false).SetFields("id"))
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields("id").Union(NewPQSelect(archived, false).SetFields("notexist"))
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFields("id").Union(NewPQSelect(archived, false).SetFields("id"))
+	testError(t, b, false)
+}
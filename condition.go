@@ -0,0 +1,315 @@
+package qb
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//Condition is a boolean expression for the where clause.
+//Use Filter to create a condition for a field, and And, Or and Not to combine the conditions.
+type Condition interface {
+	asFilter() filter
+}
+
+//filter is a condition for a field, or a group of conditions when op is AND, OR or NOT.
+//The group created by And, Or and Not always have non nil conds.
+type filter struct {
+	field string
+	op    string
+	value interface{}
+	conds []filter
+	//expr is rendered instead of the field when the field is the alias of an expression.
+	expr *Expr
+}
+
+const (
+	opAnd   = "AND"
+	opOr    = "OR"
+	opNot   = "NOT"
+	opIn    = "IN"
+	opNotIn = "NOT IN"
+
+	opIsNull    = "IS NULL"
+	opIsNotNull = "IS NOT NULL"
+
+	opLike     = "LIKE"
+	opNotLike  = "NOT LIKE"
+	opILike    = "ILIKE"
+	opNotILike = "NOT ILIKE"
+
+	//opNil is the op of the nil condition so it is reported as an error instead of panic.
+	opNil = "NIL"
+)
+
+func (f filter) asFilter() filter {
+	return f
+}
+
+func (f filter) isGroup() bool {
+	return f.op == opAnd || f.op == opOr || f.op == opNot
+}
+
+//asFilter return the filter of cond, the nil cond is kept as a filter with opNil.
+func asFilter(cond Condition) filter {
+	if cond == nil {
+		return filter{op: opNil}
+	}
+	return cond.asFilter()
+}
+
+//opError report the nil condition and the group op that is passed to SetFilter.
+func (f filter) opError() error {
+	if f.op == opNil {
+		return errors.New("condition is nil")
+	}
+	if f.isGroup() && f.conds == nil {
+		return fmt.Errorf("filter op %s is not supported, use And, Or or Not", f.op)
+	}
+	return nil
+}
+
+//filtersOpError check opError of the filters and the filters in the groups.
+func filtersOpError(filters []filter) error {
+	for _, f := range filters {
+		if err := f.opError(); err != nil {
+			return err
+		}
+		if err := filtersOpError(f.conds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Filter return a condition that compare the field with the value using op,
+//the supported op is the same as Select.SetFilter.
+func Filter(field, op string, value interface{}) Condition {
+	return newFilter(field, op, value)
+}
+
+//newFilter create a filter where the nil value compared with = or <> is translated to
+//IS NULL or IS NOT NULL as comparing to NULL never match.
+func newFilter(field, op string, value interface{}) filter {
+	op = strings.Join(strings.Fields(strings.ToUpper(op)), " ")
+	if isNull(value) {
+		switch op {
+		case "=":
+			op = opIsNull
+		case "<>", "!=":
+			op = opIsNotNull
+		}
+	}
+	if isNullOp(op) {
+		value = nil
+	}
+	return filter{field: strings.ToLower(field), op: op, value: value}
+}
+
+//isNull report whether v is written as NULL to the database.
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return true
+		}
+		dv, err := valuer.Value()
+		return err == nil && dv == nil
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+func isLikeOp(op string) bool {
+	return op == opLike || op == opNotLike || op == opILike || op == opNotILike
+}
+
+func isNullOp(op string) bool {
+	return op == opIsNull || op == opIsNotNull
+}
+
+//Contains return a condition that match when the field contains s,
+//the wildcard character in s is escaped so s is matched as is.
+func Contains(field, s string) Condition {
+	return Filter(field, opLike, "%"+EscapeLike(s)+"%")
+}
+
+//StartsWith return a condition that match when the field starts with s,
+//the wildcard character in s is escaped so s is matched as is.
+func StartsWith(field, s string) Condition {
+	return Filter(field, opLike, EscapeLike(s)+"%")
+}
+
+//EndsWith return a condition that match when the field ends with s,
+//the wildcard character in s is escaped so s is matched as is.
+func EndsWith(field, s string) Condition {
+	return Filter(field, opLike, "%"+EscapeLike(s))
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "[", `\[`)
+
+//EscapeLike escape the wildcard character % and _ and the escape character backslash in s,
+//so s can be used as a part of LIKE pattern. [ is also escaped as it is a wildcard on SQL Server.
+//	Filter("name", "ILIKE", "%"+EscapeLike(s)+"%")
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+//And return a condition that is true when all of the conds are true.
+func And(conds ...Condition) Condition {
+	return group(opAnd, conds)
+}
+
+//Or return a condition that is true when any of the conds is true.
+func Or(conds ...Condition) Condition {
+	return group(opOr, conds)
+}
+
+//Not return a condition that negate cond.
+func Not(cond Condition) Condition {
+	return group(opNot, []Condition{cond})
+}
+
+func group(op string, conds []Condition) filter {
+	f := filter{op: op, conds: make([]filter, len(conds))}
+	for i, cond := range conds {
+		f.conds[i] = asFilter(cond)
+	}
+	return f
+}
+
+//build return the query of the filter with the placeholder number starting from starting.
+func (f filter) build(d Dialect, starting int) (query string, args []interface{}, next int) {
+	//the group op that is passed to SetFilter has nil conds, it is rendered as a comparison, see opError.
+	if f.isGroup() && f.conds != nil {
+		return f.buildGroup(d, starting)
+	}
+	values, isList := listValues(f.value)
+	if isListOp(f.op) && isList && len(values) == 0 {
+		//nothing is IN an empty list.
+		if f.op == opIn {
+			return "1 = 0", nil, starting
+		}
+		return "1 = 1", nil, starting
+	}
+	//the field is rendered before the value so the placeholder is numbered in order.
+	field, args, starting := f.fieldQuery(d, starting)
+	if isNullOp(f.op) {
+		return field + " " + f.op, args, starting
+	}
+	if isListOp(f.op) && isList {
+		query = field + " " + f.op + " (" + makePlaceholder(d, starting, len(values)) + ")"
+		return query, append(args, values...), starting + len(values)
+	}
+	value, valueArgs, next := f.valueQuery(d, starting)
+	args = append(args, valueArgs...)
+	switch {
+	case isExistsOp(f.op):
+		query = f.op + " " + value
+	case isLikeOp(f.op):
+		query = like(d, field, f.op, value)
+	default:
+		query = field + " " + f.op + " " + value
+	}
+	return query, args, next
+}
+
+//buildGroup return the query of AND, OR or NOT group.
+func (f filter) buildGroup(d Dialect, starting int) (query string, args []interface{}, next int) {
+	switch f.op {
+	case opAnd, opOr:
+		if len(f.conds) == 0 {
+			//empty AND is always true and empty OR is always false.
+			if f.op == opAnd {
+				return "1 = 1", nil, starting
+			}
+			return "1 = 0", nil, starting
+		}
+		w := make([]string, len(f.conds))
+		for i, cond := range f.conds {
+			var condArgs []interface{}
+			w[i], condArgs, starting = cond.build(d, starting)
+			args = append(args, condArgs...)
+		}
+		query = "(" + strings.Join(w, " "+f.op+" ") + ")"
+		return query, args, starting
+	default:
+		cond := f.conds[0]
+		query, args, next = cond.build(d, starting)
+		if !cond.isGroup() || cond.op == opNot {
+			query = "(" + query + ")"
+		}
+		return "NOT " + query, args, next
+	}
+}
+
+//fieldQuery return the quoted field or the expression of the field.
+func (f filter) fieldQuery(d Dialect, starting int) (query string, args []interface{}, next int) {
+	if f.expr != nil {
+		return f.expr.build(d, starting)
+	}
+	if f.field == "" {
+		return "", nil, starting
+	}
+	return quoteField(d, f.field), nil, starting
+}
+
+//valueQuery return the placeholder of the value, or the query when the value is a field,
+//a subquery or an expression.
+func (f filter) valueQuery(d Dialect, starting int) (query string, args []interface{}, next int) {
+	switch v := f.value.(type) {
+	case Col:
+		return d.Quote(string(v)), nil, starting
+	case *Select:
+		query, args, next = v.nestedQuery(starting)
+		return "(" + query + ")", args, next
+	case Expr:
+		query, args, next = v.build(d, starting)
+		return "(" + query + ")", args, next
+	}
+	return d.Placeholder(starting), []interface{}{f.value}, starting + 1
+}
+
+//hasQueryValue report whether the filter or the filter in the group use a subquery
+//or an expression as the value.
+func (f filter) hasQueryValue() bool {
+	if f.isGroup() {
+		for _, cond := range f.conds {
+			if cond.hasQueryValue() {
+				return true
+			}
+		}
+		return false
+	}
+	switch f.value.(type) {
+	case *Select, Expr:
+		return true
+	}
+	return false
+}
+
+func isListOp(op string) bool {
+	return op == opIn || op == opNotIn
+}
+
+//listValues return the elements of slice or array v, []byte is not treated as a slice
+//as it is a single value for the database.
+func listValues(v interface{}) ([]interface{}, bool) {
+	if _, ok := v.([]byte); ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	values := make([]interface{}, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
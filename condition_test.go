@@ -0,0 +1,182 @@
+package qb
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestWhereQuery(t *testing.T) {
+	type Emp struct {
+		ID     string `pk:"1"`
+		Name   string
+		Status string
+		Age    int
+	}
+	b := newBuilder(t, Emp{}, false)
+	b.SetFilter("age", ">", 20)
+	b.Where(Or(Filter("status", "=", "a"), Filter("Status", "=", "b")))
+	wantQ := "SELECT * FROM emp WHERE age > $1 AND (status = $2 OR status = $3) ORDER BY id"
+	wantA := []interface{}{20, "a", "b"}
+	testQuery(t, b, wantQ, wantA)
+
+	b.Reset()
+	b.Where(Or(
+		And(Filter("name", "=", "a"), Filter("age", "<", 30)),
+		Filter("status", "=", "c"),
+	), Not(Filter("age", "=", 40)))
+	wantQ = "SELECT * FROM emp WHERE ((name = $1 AND age < $2) OR status = $3) AND NOT (age = $4) ORDER BY id"
+	wantA = []interface{}{"a", 30, "c", 40}
+	testQuery(t, b, wantQ, wantA)
+
+	b.Reset()
+	b.Where(Not(Or(Filter("name", "=", "a"), Filter("name", "=", "b"))), Or(), And())
+	wantQ = "SELECT * FROM emp WHERE NOT (name = $1 OR name = $2) AND 1 = 0 AND 1 = 1 ORDER BY id"
+	wantA = []interface{}{"a", "b"}
+	testQuery(t, b, wantQ, wantA)
+}
+
+func TestWhereError(t *testing.T) {
+	type Emp struct {
+		ID     string `pk:"1"`
+		Status string
+	}
+	b := newBuilder(t, Emp{}, false)
+	b.Where(Or(Filter("status", "=", "a"), Not(Filter("id", "=", "b"))))
+	testError(t, b, false)
+	b.Reset()
+	b.Where(Or(Filter("status", "=", "a"), Not(Filter("FieldNotExist", "=", "b"))))
+	testError(t, b, true)
+	b.Reset()
+	b.Where(And(Filter("status", "=", "a"), Filter("id", "!!", "b")))
+	testError(t, b, true)
+
+	//nil condition and the group op used as the filter op.
+	b.Reset()
+	b.Where(Not(nil))
+	testError(t, b, true)
+	b.Reset()
+	b.Where(And(Filter("status", "=", "a"), nil))
+	testError(t, b, true)
+	b.Reset()
+	b.Where(nil)
+	testError(t, b, true)
+	for _, op := range []string{"and", "OR", "not"} {
+		b.Reset()
+		b.SetFilter("status", op, "a")
+		testError(t, b, true)
+		b.Reset()
+		b.Where(Or(Filter("status", op, "a")))
+		testError(t, b, true)
+	}
+
+	u := newUpdateBuilder(t, Emp{})
+	u.Set("status", "a")
+	u.SetFilter("status", "NOT", "b")
+	if err := u.Update(nil); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+	u.Where(Not(nil))
+	if err := u.Delete(nil); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+}
+
+func TestUpdateWhereQuery(t *testing.T) {
+	type Emp struct {
+		ID     string `pk:"1"`
+		Name   string
+		Status string
+	}
+	b := newUpdateBuilder(t, Emp{})
+	b.Set("name", "al")
+	b.Where(Or(Filter("status", "=", "a"), Filter("status", "=", "b")))
+	wantQ := "UPDATE emp SET name = $1 WHERE (status = $2 OR status = $3)"
+	wantA := []interface{}{"al", "a", "b"}
+	testUpdateQuery(t, b, wantQ, wantA)
+}
+
+func TestInFilterQuery(t *testing.T) {
+	type Emp struct {
+		ID   string `pk:"1"`
+		Name string
+		Age  int
+	}
+	b := newBuilder(t, Emp{}, false)
+	b.SetFilter("id", "in", []string{"a", "b", "c"})
+	b.SetFilter("age", "NOT IN", [2]int{20, 30})
+	wantQ := "SELECT * FROM emp WHERE id IN ($1,$2,$3) AND age NOT IN ($4,$5) ORDER BY id"
+	wantA := []interface{}{"a", "b", "c", 20, 30}
+	testQuery(t, b, wantQ, wantA)
+	testError(t, b, false)
+
+	b.Reset()
+	b.Where(Or(Filter("id", "IN", []string{}), Filter("age", "not  in", []int{})))
+	wantQ = "SELECT * FROM emp WHERE (1 = 0 OR 1 = 1) ORDER BY id"
+	testQuery(t, b, wantQ, nil)
+
+	b.Reset()
+	b.SetFilter("id", "IN", "a")
+	testError(t, b, true)
+
+	u := newUpdateBuilder(t, Emp{})
+	u.Set("name", "al")
+	u.SetFilter("id", "IN", []string{"a", "b"})
+	testUpdateQuery(t, u, "UPDATE emp SET name = $1 WHERE id IN ($2,$3)", []interface{}{"al", "a", "b"})
+}
+
+func TestNullFilterQuery(t *testing.T) {
+	type Emp struct {
+		ID        string `pk:"1"`
+		Name      string
+		DeletedAt *time.Time
+	}
+	b := newBuilder(t, Emp{}, false)
+	var deletedAt *time.Time
+	b.SetFilter("deleted_at", "=", nil)
+	b.SetFilter("deletedat", "<>", deletedAt)
+	b.SetFilter("name", "=", sql.NullString{})
+	b.SetFilter("name", "is not null", "ignored")
+	b.SetFilter("id", "=", "a")
+	wantQ := "SELECT * FROM emp WHERE deleted_at IS NULL AND deletedat IS NOT NULL AND name IS NULL" +
+		" AND name IS NOT NULL AND id = $1 ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"a"})
+
+	b.Reset()
+	b.Where(Or(Filter("deletedat", "IS NULL", nil), Filter("deletedat", ">", "2010-01-01")))
+	testError(t, b, false)
+	wantQ = "SELECT * FROM emp WHERE (deletedat IS NULL OR deletedat > $1) ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"2010-01-01"})
+
+	u := newUpdateBuilder(t, Emp{})
+	u.Set("name", "al")
+	u.SetFilter("deletedat", "=", nil)
+	testUpdateQuery(t, u, "UPDATE emp SET name = $1 WHERE deletedat IS NULL", []interface{}{"al"})
+}
+
+func TestLikeFilterQuery(t *testing.T) {
+	type Emp struct {
+		ID   string `pk:"1"`
+		Name string
+	}
+	tbl, err := NewTable("", Emp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	var tests = []struct {
+		d     Dialect
+		wantQ string
+	}{
+		{PQ{}, "SELECT * FROM emp WHERE name LIKE $1 AND name NOT ILIKE $2 AND id LIKE $3 ORDER BY id"},
+		{MySQL{}, "SELECT * FROM `emp` WHERE `name` LIKE ? AND lower(`name`) NOT LIKE lower(?)" +
+			" AND `id` LIKE ? ORDER BY `id`"},
+		{SQLite{}, `SELECT * FROM "emp" WHERE "name" LIKE ? ESCAPE '\' AND lower("name") NOT LIKE lower(?) ESCAPE '\'` +
+			` AND "id" LIKE ? ESCAPE '\' ORDER BY "id"`},
+	}
+	for _, test := range tests {
+		b := NewSelect(test.d, tbl, false)
+		b.Where(Contains("name", `5%_off\`), Filter("name", "not ilike", "a%"), StartsWith("id", "[x]"))
+		testError(t, b, false)
+		testQuery(t, b, test.wantQ, []interface{}{`%5\%\_off\\%`, "a%", `\[x]%`})
+	}
+}
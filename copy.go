@@ -0,0 +1,115 @@
+package qb
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+//CopyIn return the statement to copy the rows to the table, the statement is prepared and
+//executed once for each row and once without args to flush the rows. pq.CopyIn is a CopyIn.
+type CopyIn func(table string, columns ...string) string
+
+//CopyExecer is an interface to prepare and execute the statement, sql.Tx implement CopyExecer.
+//lib/pq only support COPY inside a transaction.
+type CopyExecer interface {
+	DBExecer
+	Prepare(query string) (*sql.Stmt, error)
+}
+
+//copySupporter is implemented by the Dialect that support COPY.
+type copySupporter interface {
+	SupportCopy() bool
+}
+
+//Load stream the rows of src to the table using COPY, src must be slice or channel of struct
+//or pointer to struct, the channel is read until it is closed.
+//When copyIn is nil or the dialect doesn't support COPY, the rows is inserted in chunks
+//like InsertMany. It return the number of rows loaded, the channel is not read anymore
+//when it return an error.
+//	tx, _ := db.Begin()
+//	n, err := u.Load(tx, pq.CopyIn, rows)
+func (u *Update) Load(dbe CopyExecer, copyIn CopyIn, src interface{}) (int64, error) {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Chan {
+		return 0, errors.New("src must be slice or channel")
+	}
+	if c, ok := u.d.(copySupporter); copyIn == nil || !ok || !c.SupportCopy() {
+		return u.loadInsert(dbe, rv)
+	}
+	stmt, err := dbe.Prepare(copyIn(u.t.TableName(), u.t.Fields()...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	var n int64
+	err = eachValue(rv, func(v reflect.Value) error {
+		args, err := u.getArgs(v.Interface())
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	if _, err = stmt.Exec(); err != nil {
+		return n, err
+	}
+	return n, stmt.Close()
+}
+
+//loadInsert insert the rows in chunks, the rows of the channel is collected until the chunk is full.
+func (u *Update) loadInsert(dbe DBExecer, rv reflect.Value) (int64, error) {
+	if rv.Kind() == reflect.Slice {
+		return u.InsertMany(dbe, rv.Interface())
+	}
+	size := u.chunkSize(len(u.t.Fields()))
+	chunk := reflect.MakeSlice(reflect.SliceOf(rv.Type().Elem()), 0, size)
+	progress := u.progress
+	u.progress = nil
+	defer func() { u.progress = progress }()
+	var affected int64
+	insert := func() error {
+		n, err := u.InsertMany(dbe, chunk.Interface())
+		affected += n
+		chunk = chunk.Slice(0, 0)
+		return err
+	}
+	err := eachValue(rv, func(v reflect.Value) error {
+		chunk = reflect.Append(chunk, v)
+		if chunk.Len() < size {
+			return nil
+		}
+		return insert()
+	})
+	if err == nil && chunk.Len() > 0 {
+		err = insert()
+	}
+	return affected, err
+}
+
+//eachValue call fn for each element of the slice or each value received from the channel.
+func eachValue(rv reflect.Value, fn func(v reflect.Value) error) error {
+	if rv.Kind() == reflect.Slice {
+		for i := 0; i < rv.Len(); i++ {
+			if err := fn(rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for {
+		v, ok := rv.Recv()
+		if !ok {
+			return nil
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+}
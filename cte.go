@@ -0,0 +1,94 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+type cte struct {
+	name      string
+	s         *Select
+	recursive *Select
+}
+
+//CTE return a table to refer the common table expression name from the main query
+//or from the recursive part, the fields of the table is the fields selected by s.
+//	tree := CTE("tree", anchor)
+//	NewPQSelect(tree, false).WithRecursive("tree", anchor, recursive)
+func CTE(name string, s *Select) Tabler {
+	return cteTable{derived{s: s, alias: strings.ToLower(name)}}
+}
+
+//cteTable is rendered using the name of the common table expression instead of the query.
+type cteTable struct {
+	derived
+}
+
+//With add the common table expression name with the query of s before the select query,
+//the placeholder of the main query is numbered after the placeholder of the expression.
+//The expression is kept when the select is Reset.
+func (s *Select) With(name string, sub *Select) *Select {
+	s.ctes = append(s.ctes, cte{name: strings.ToLower(name), s: sub})
+	return s
+}
+
+//WithRecursive add the recursive common table expression name which is anchor UNION ALL recursive,
+//the recursive part refer the expression using the CTE table.
+//ORDER BY and LIMIT of anchor and recursive is not rendered as it is not allowed in the recursive query.
+//	anchor := NewPQSelect(emp, false).SetFields("id", "managerid").SetFilter("managerid", "=", nil)
+//	tree := CTE("tree", anchor)
+//	recursive := NewPQSelect(emp, false).As("e").SetFields("e.id", "e.managerid").
+//		Join(tree, "", On("e.managerid", "tree.id"))
+//	s := NewPQSelect(tree, false).WithRecursive("tree", anchor, recursive)
+func (s *Select) WithRecursive(name string, anchor, recursive *Select) *Select {
+	s.ctes = append(s.ctes, cte{name: strings.ToLower(name), s: anchor, recursive: recursive})
+	return s
+}
+
+//withQuery return the WITH clause including the trailing space.
+func (s *Select) withQuery(starting int) (query string, args []interface{}, next int) {
+	if len(s.ctes) == 0 {
+		return "", nil, starting
+	}
+	keyword := "WITH "
+	w := make([]string, len(s.ctes))
+	for i, c := range s.ctes {
+		var q string
+		var cteArgs []interface{}
+		if c.recursive == nil {
+			q, cteArgs, starting = c.s.nestedQuery(starting)
+		} else {
+			keyword = "WITH RECURSIVE "
+			if r, ok := s.d.(recursiver); ok {
+				keyword = "WITH " + r.Recursive()
+			}
+			var anchor, recursive string
+			var recursiveArgs []interface{}
+			anchor, cteArgs, starting = c.s.bodyQuery("", starting)
+			recursive, recursiveArgs, starting = c.recursive.bodyQuery("", starting)
+			q = anchor + " UNION ALL " + recursive
+			cteArgs = append(cteArgs, recursiveArgs...)
+		}
+		w[i] = s.d.Quote(c.name) + " AS (" + q + ")"
+		args = append(args, cteArgs...)
+	}
+	return keyword + strings.Join(w, ",") + " ", args, starting
+}
+
+func (s *Select) cteError() error {
+	for _, c := range s.ctes {
+		if err := c.s.Error(); err != nil {
+			return fmt.Errorf("cte %s: %v", c.name, err)
+		}
+		if c.recursive == nil {
+			continue
+		}
+		if err := c.recursive.Error(); err != nil {
+			return fmt.Errorf("cte %s: %v", c.name, err)
+		}
+		if len(c.s.scanFields()) != len(c.recursive.scanFields()) {
+			return fmt.Errorf("cte %s: recursive fields doesn't match the anchor fields", c.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package qb
+
+import "testing"
+
+type cteNode struct {
+	ID       string `pk:"1"`
+	ParentID string
+	Name     string
+}
+
+func newCTERecursive(t *testing.T, d Dialect) (anchor, recursive *Select, tree Tabler) {
+	node, err := NewTable("node", cteNode{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	anchor = NewSelect(d, node, false).SetFields("id", "parentid", "name").SetFilter("id", "=", "root")
+	tree = CTE("tree", anchor)
+	recursive = NewSelect(d, node, false).As("n").SetFields("n.id", "n.parentid", "n.name").
+		Join(tree, "", On("n.parentid", "tree.id")).SetFilter("n.name", "<>", "skip")
+	return anchor, recursive, tree
+}
+
+func TestWithQuery(t *testing.T) {
+	emp, _ := newSubqueryTables(t)
+	active := NewPQSelect(emp, false).SetFields("id", "name").SetFilter("deptid", "=", "it")
+	b := NewPQSelect(CTE("active", active), false).With("Active", active)
+	b.SetFilter("name", "LIKE", "a%").SetLimit(2)
+	wantQ := "WITH active AS (SELECT id,name FROM emp WHERE deptid = $1)" +
+		" SELECT * FROM active WHERE name LIKE $2 ORDER BY id LIMIT 2"
+	testQuery(t, b, wantQ, []interface{}{"it", "a%"})
+	testError(t, b, false)
+
+	wantQ = "WITH active AS (SELECT id,name FROM emp WHERE deptid = $1)" +
+		" SELECT * FROM active WHERE id = $2"
+	if got := b.SelectByPK(); got != wantQ {
+		t.Errorf("got query: %s \n             want %s", got, wantQ)
+	}
+	wantQ = "WITH active AS (SELECT id,name FROM emp WHERE deptid = $1)" +
+		" SELECT * FROM (SELECT * FROM active WHERE name LIKE $2 ORDER BY id DESC LIMIT 2) AS xxlast ORDER BY id"
+	if got, _ := b.lastQuery(); got != wantQ {
+		t.Errorf("got query: %s \n             want %s", got, wantQ)
+	}
+
+	b.Reset()
+	b.SetFilter("notexist", "=", 1)
+	testError(t, b, true)
+}
+
+func TestWithNumbering(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	b := NewPQSelect(emp, false).With("x", NewPQSelect(dept, false)).SetFilter("name", "=", "a")
+	wantQ := "WITH x AS (SELECT * FROM dept) SELECT * FROM emp WHERE name = $1 ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"a"})
+}
+
+func TestWithRecursiveQuery(t *testing.T) {
+	anchor, recursive, tree := newCTERecursive(t, PQ{})
+	b := NewPQSelect(tree, false).WithRecursive("tree", anchor, recursive).SetFilter("name", "<>", "x")
+	wantQ := "WITH RECURSIVE tree AS (SELECT id,parentid,name FROM node WHERE id = $1 UNION ALL" +
+		" SELECT n.id,n.parentid,n.name FROM node AS n INNER JOIN tree ON n.parentid = tree.id WHERE n.name <> $2)" +
+		" SELECT * FROM tree WHERE name <> $3 ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"root", "skip", "x"})
+	testError(t, b, false)
+
+	anchor, recursive, tree = newCTERecursive(t, SQLServer{})
+	b = NewSQLServerSelect(tree, false).WithRecursive("tree", anchor, recursive)
+	wantQ = "WITH [tree] AS (SELECT [id],[parentid],[name] FROM [node] WHERE [id] = @p1 UNION ALL" +
+		" SELECT [n].[id],[n].[parentid],[n].[name] FROM [node] AS [n] INNER JOIN [tree] ON [n].[parentid] = [tree].[id]" +
+		" WHERE [n].[name] <> @p2) SELECT * FROM [tree] ORDER BY [id]"
+	testQuery(t, b, wantQ, []interface{}{"root", "skip"})
+}
+
+func TestWithRecursiveError(t *testing.T) {
+	anchor, recursive, tree := newCTERecursive(t, PQ{})
+	recursive.SetFields("n.name")
+	b := NewPQSelect(tree, false).WithRecursive("tree", anchor, recursive)
+	testError(t, b, true)
+
+	anchor, recursive, tree = newCTERecursive(t, PQ{})
+	recursive.SetFilter("tree.notexist", "=", 1)
+	b = NewPQSelect(tree, false).WithRecursive("tree", anchor, recursive)
+	testError(t, b, true)
+}
@@ -3,7 +3,9 @@ package qb
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 )
@@ -19,6 +21,9 @@ type Cursor struct {
 
 var sep = []byte("\n")
 
+//colPrefix mark the filter value as Col, it is never produced by url.QueryEscape.
+var colPrefix = []byte("!")
+
 //Decode decodes the string.
 func (c Cursor) Decode(s string) (Cursor, error) {
 	data, err := base64.StdEncoding.DecodeString(s)
@@ -27,7 +32,9 @@ func (c Cursor) Decode(s string) (Cursor, error) {
 	}
 	ds := bytes.Split(data, sep)
 	c.fields = c.decodeToSS(ds[0])
-	c.filters = c.decodeFilters(ds[1])
+	if c.filters, err = c.decodeFilters(ds[1]); err != nil {
+		return c, err
+	}
 	c.orderBy = c.decodeToSS(ds[2])
 
 	limit, err := strconv.Atoi(string(ds[3]))
@@ -54,24 +61,100 @@ func (c Cursor) decodeToSS(data []byte) []string {
 	return result
 }
 
-func (c Cursor) decodeFilters(data []byte) []filter {
-	var filters []filter
-	if len(data) == 0 {
-		return filters
-	}
-	ds := bytes.Split(data, []byte(";"))
-	for _, v := range ds {
-		if len(v) != 0 {
-			vs := bytes.Split(v, []byte(","))
-			filters = append(filters,
-				filter{
-					field: string(vs[0]),
-					op:    string(vs[1]),
-					value: string(vs[2]),
-				})
+func (c Cursor) decodeFilters(data []byte) ([]filter, error) {
+	filters, rest, err := c.decodeFilterList(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("cursor: invalid filters")
+	}
+	return filters, nil
+}
+
+//decodeFilterList decode the filters until the end of the data or the closing of the group,
+//it return the data after the closing of the group.
+func (c Cursor) decodeFilterList(data []byte) (filters []filter, rest []byte, err error) {
+	for len(data) != 0 {
+		if data[0] == ')' {
+			return filters, data[1:], nil
+		}
+		var f filter
+		f, data, err = c.decodeFilter(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		filters = append(filters, f)
+		if len(data) != 0 && data[0] == ';' {
+			data = data[1:]
+		}
+	}
+	return filters, data, nil
+}
+
+//decodeFilter decode a filter or a group of filters written as op(filter;filter;).
+func (c Cursor) decodeFilter(data []byte) (f filter, rest []byte, err error) {
+	i := bytes.IndexAny(data, "(;")
+	if i >= 0 && data[i] == '(' {
+		f.op = string(data[:i])
+		f.conds, rest, err = c.decodeFilterList(data[i+1:])
+		return f, rest, err
+	}
+	if i < 0 {
+		i = len(data)
+	}
+	vs := bytes.Split(data[:i], []byte(","))
+	if len(vs) < 2 {
+		return f, nil, errors.New("cursor: invalid filter " + string(data[:i]))
+	}
+	ss := make([]string, len(vs))
+	for j, v := range vs {
+		if ss[j], err = url.QueryUnescape(string(v)); err != nil {
+			return f, nil, err
+		}
+	}
+	f.field, f.op = ss[0], ss[1]
+	switch {
+	case isListOp(f.op):
+		f.value = ss[2:]
+	case isNullOp(f.op) && len(ss) == 2:
+	case len(ss) == 3 && bytes.HasPrefix(vs[2], colPrefix):
+		f.value = Col(strings.TrimPrefix(ss[2], string(colPrefix)))
+	case len(ss) == 3:
+		f.value = ss[2]
+	default:
+		return f, nil, errors.New("cursor: invalid filter " + string(data[:i]))
+	}
+	return f, data[i:], nil
+}
+
+func (c Cursor) encodeFilter(buf *bytes.Buffer, f filter) {
+	if f.isGroup() {
+		buf.WriteString(f.op + "(")
+		for _, cond := range f.conds {
+			c.encodeFilter(buf, cond)
+			buf.WriteString(";")
+		}
+		buf.WriteString(")")
+		return
+	}
+	fmt.Fprintf(buf, "%s,%s", url.QueryEscape(f.field), url.QueryEscape(f.op))
+	//the values of IN and NOT IN is written as separated value.
+	values := []interface{}{f.value}
+	switch {
+	case isListOp(f.op):
+		values, _ = listValues(f.value)
+	case isNullOp(f.op):
+		values = nil
+	}
+	for _, v := range values {
+		buf.WriteString(",")
+		if col, ok := v.(Col); ok {
+			buf.Write(colPrefix)
+			v = string(col)
 		}
+		buf.WriteString(url.QueryEscape(fmt.Sprintf("%v", v)))
 	}
-	return filters
 }
 
 //String return base64 string representation of the cursor.
@@ -82,7 +165,8 @@ func (c Cursor) String() string {
 	}
 	buf.Write(sep)
 	for _, filter := range c.filters {
-		fmt.Fprintf(buf, "%s,%s,%v;", filter.field, filter.op, filter.value)
+		c.encodeFilter(buf, filter)
+		buf.WriteString(";")
 	}
 	buf.Write(sep)
 	buf.WriteString(strings.Join(c.orderBy, ","))
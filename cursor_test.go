@@ -45,6 +45,39 @@ func TestCursor(t *testing.T) {
 			limit:  10,
 			offset: 50,
 		},
+		{
+			filters: []filter{
+				{field: "amount", op: ">", value: "1,5;(x)"},
+				{op: "OR", conds: []filter{
+					{field: "status", op: "=", value: "a"},
+					{op: "NOT", conds: []filter{
+						{field: "status", op: "=", value: "b c"},
+					}},
+				}},
+			},
+			limit:  10,
+			offset: 50,
+		},
+		{
+			filters: []filter{
+				{field: "id", op: "IN", value: []string{"a,b", "c"}},
+				{field: "id", op: "NOT IN", value: []string{}},
+				{field: "id", op: "IN", value: []string{""}},
+			},
+			limit: 10,
+		},
+		{
+			filters: []filter{
+				{field: "deletedat", op: "IS NULL"},
+				{op: "OR", conds: []filter{
+					{field: "name", op: "IS NOT NULL"},
+					{field: "name", op: "=", value: "<nil>"},
+					{field: "e.deptid", op: "=", value: Col("d.id")},
+					{field: "name", op: "=", value: "!d.id"},
+				}},
+			},
+			limit: 10,
+		},
 	}
 	for _, c := range tests {
 		s := c.String()
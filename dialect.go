@@ -0,0 +1,202 @@
+package qb
+
+import (
+	"strings"
+)
+
+//Dialect is an interface that render the database specific part of the query.
+//The builders use the Dialect for every part of the query that differ between databases,
+//so implementing Dialect is enough to make Select and Update target another database.
+type Dialect interface {
+	//Placeholder return the placeholder for n-th argument, n is starting from 1.
+	Placeholder(n int) string
+	//Quote quote the identifier so it can be used as table or field name.
+	Quote(identifier string) string
+	//LimitOffset return the clause to limit the rows, it is appended after the ORDER BY clause.
+	//limit or offset that is less than or equal to zero is not rendered.
+	LimitOffset(limit, offset int) string
+	//Insert return an insert query for rows number of rows with the placeholder starting from 1.
+	//returning is the fields to get back from the inserted rows and is ignored
+	//when the dialect doesn't support returning.
+	Insert(table string, fields []string, rows int, returning []string) string
+	//SupportReturning report whether Insert can return the fields of the inserted rows.
+	SupportReturning() bool
+	//Upsert return an insert query that update the update fields when the row
+	//conflict with the conflict fields, when update is empty the conflicted row is left unchanged.
+	Upsert(table string, fields []string, rows int, conflict, update []string) string
+}
+
+func whereQuery(d Dialect, fields []string, starting int) (query string, next int) {
+	query, next = fieldsWithPlaceholder(d, fields, starting)
+	query = " WHERE " + query
+	return
+}
+
+func updateSetQuery(d Dialect, fvs []fieldValue, starting int) (query string, args []interface{}, next int) {
+	w := make([]string, len(fvs))
+	for i, fv := range fvs {
+		switch v := fv.value.(type) {
+		case Expr:
+			var exprArgs []interface{}
+			w[i], exprArgs, starting = v.build(d, starting)
+			if v.column {
+				w[i] = d.Quote(fv.field) + " " + w[i]
+			}
+			w[i] = d.Quote(fv.field) + " = " + w[i]
+			args = append(args, exprArgs...)
+			continue
+		case Col:
+			w[i] = d.Quote(fv.field) + " = " + d.Quote(string(v))
+			continue
+		}
+		w[i] = d.Quote(fv.field) + " = " + d.Placeholder(starting)
+		args = append(args, fv.value)
+		starting++
+	}
+	query = strings.Join(w, ",")
+	next = starting
+	query = " SET " + query
+	return
+}
+
+func fieldsWithPlaceholder(d Dialect, fields []string, starting int) (query string, next int) {
+	w := make([]string, len(fields))
+	for i, field := range fields {
+		w[i] = d.Quote(field) + " = " + d.Placeholder(starting)
+		starting++
+	}
+	query = strings.Join(w, " AND ")
+	next = starting
+	return
+}
+
+func filterQuery(d Dialect, filters []filter, starting int) (where string, args []interface{}, next int) {
+	if len(filters) == 0 {
+		return "", nil, starting
+	}
+	w := make([]string, len(filters))
+	for i, filter := range filters {
+		var filterArgs []interface{}
+		w[i], filterArgs, starting = filter.build(d, starting)
+		args = append(args, filterArgs...)
+	}
+	next = starting
+	where = " WHERE " + strings.Join(w, " AND ")
+	return where, args, next
+}
+
+//makePlaceholder return n placeholders separated by comma with the number starting from starting.
+func makePlaceholder(d Dialect, starting, n int) string {
+	p := make([]string, n)
+	for i := 0; i < n; i++ {
+		p[i] = d.Placeholder(starting + i)
+	}
+	return strings.Join(p, ",")
+}
+
+func quoteFields(d Dialect, fields []string) string {
+	q := make([]string, len(fields))
+	for i, field := range fields {
+		q[i] = d.Quote(field)
+	}
+	return strings.Join(q, ",")
+}
+
+//valuesQuery return the VALUES clause for rows number of rows with n placeholders each.
+func valuesQuery(d Dialect, n, rows int) string {
+	v := make([]string, rows)
+	for i := 0; i < rows; i++ {
+		v[i] = "(" + makePlaceholder(d, i*n+1, n) + ")"
+	}
+	return " VALUES " + strings.Join(v, ",")
+}
+
+func insertQuery(d Dialect, table string, fields []string, rows int) string {
+	return "INSERT INTO " + d.Quote(table) + " (" + quoteFields(d, fields) + ")" +
+		valuesQuery(d, len(fields), rows)
+}
+
+//quoteIdentifier quote every part of the identifier qualified with dot using open and close,
+//close inside the identifier is escaped by doubling it.
+func quoteIdentifier(identifier, open, close string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = open + strings.Replace(part, close, close+close, -1) + close
+	}
+	return strings.Join(parts, ".")
+}
+
+//topper is implemented by the Dialect that limit the rows using a clause after SELECT,
+//the clause is rendered with the trailing space.
+type topper interface {
+	Top(limit, offset int) string
+	//Fetch return the clause after ORDER BY to limit the rows when TOP can't be used,
+	//e.g. for the compound query.
+	Fetch(limit int) string
+}
+
+//locker is implemented by the Dialect that support the row locking clause,
+//strength is UPDATE or SHARE and wait is SKIP LOCKED, NOWAIT or empty.
+type locker interface {
+	Lock(strength, wait string) string
+}
+
+//distinctOner is implemented by the Dialect that support DISTINCT ON,
+//the clause is rendered with the trailing space.
+type distinctOner interface {
+	DistinctOn(fields []string) string
+}
+
+//batchLimiter is implemented by the Dialect to limit the number of args of a query
+//and the number of rows of an insert query, rows is 0 when it is not limited.
+type batchLimiter interface {
+	BatchLimit() (args, rows int)
+}
+
+//defaultBatchArgs is the args limit of the Dialect that doesn't implement batchLimiter.
+const defaultBatchArgs = 999
+
+//recursiver is implemented by the Dialect that doesn't use WITH RECURSIVE for the recursive
+//common table expression, the keyword is rendered with the trailing space.
+type recursiver interface {
+	Recursive() string
+}
+
+//lockQuery return the locking clause that is used by PostgreSQL and MySQL.
+func lockQuery(strength, wait string) string {
+	query := " FOR " + strength
+	if wait != "" {
+		query += " " + wait
+	}
+	return query
+}
+
+//liker is implemented by the Dialect that doesn't use the standard pattern matching,
+//by default the pattern is escaped using ESCAPE clause and ILIKE compare the lower case of both side.
+type liker interface {
+	//Like return the pattern matching of the quoted field with the placeholder,
+	//op is LIKE, NOT LIKE, ILIKE or NOT ILIKE and the pattern is escaped with backslash.
+	Like(field, op, placeholder string) string
+}
+
+//like return the pattern matching of the field using the dialect d.
+func like(d Dialect, field, op, placeholder string) string {
+	if l, ok := d.(liker); ok {
+		return l.Like(field, op, placeholder)
+	}
+	return likeQuery(field, op, placeholder, " ESCAPE '\\'", false)
+}
+
+//likeQuery return the pattern matching query where escape is the ESCAPE clause,
+//for the database that doesn't have ILIKE, ILIKE is replaced by comparing the lower case of both side.
+func likeQuery(field, op, placeholder, escape string, ilike bool) string {
+	if !ilike && (op == opILike || op == opNotILike) {
+		op = strings.Replace(op, opILike, opLike, 1)
+		field = "lower(" + field + ")"
+		placeholder = "lower(" + placeholder + ")"
+	}
+	return field + " " + op + " " + placeholder + escape
+}
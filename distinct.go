@@ -0,0 +1,87 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Distinct remove the duplicate rows from the result of the query. When OrderBy is not called
+//the query is ordered by the selected fields, the primary keys that is not selected is not added
+//to ORDER BY as the database doesn't allow it.
+func (s *Select) Distinct() *Select {
+	s.distinct = true
+	return s
+}
+
+//DistinctOn keep only the first row of each set of rows where the fields is equal,
+//the fields must lead the ORDER BY of the query. When OrderBy is not called the query is
+//ordered by the fields and the primary keys. It is supported by PostgreSQL only.
+//	s.DistinctOn("empid").OrderBy("empid", Desc("createdat"))
+func (s *Select) DistinctOn(fieldName ...string) *Select {
+	for _, field := range fieldName {
+		s.distinctOn = append(s.distinctOn, strings.ToLower(field))
+	}
+	return s
+}
+
+//distinctQuery return DISTINCT or DISTINCT ON clause including the trailing space.
+func (s *Select) distinctQuery() string {
+	if len(s.distinctOn) != 0 {
+		if d, ok := s.d.(distinctOner); ok {
+			fields := make([]string, len(s.distinctOn))
+			for i, field := range s.distinctOn {
+				fields[i] = quoteField(s.d, field)
+			}
+			return d.DistinctOn(fields)
+		}
+	}
+	if s.distinct {
+		return "DISTINCT "
+	}
+	return ""
+}
+
+//isSelected report whether the field is selected by the query.
+func (s *Select) isSelected(field string) bool {
+	if len(s.fields) == 0 {
+		return true
+	}
+	for _, v := range s.fields {
+		if name, alias := splitAlias(v); name == field || alias == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Select) distinctError() error {
+	if len(s.distinctOn) == 0 {
+		return nil
+	}
+	if _, ok := s.d.(distinctOner); !ok {
+		return fmt.Errorf("DISTINCT ON is not supported by the dialect")
+	}
+	for _, field := range s.distinctOn {
+		if !s.fieldExist(field) {
+			return fmt.Errorf("distinct on field %s doesn't exist", field)
+		}
+	}
+	s.orderQuery(false)
+	if len(s.orderBy) < len(s.distinctOn) {
+		return fmt.Errorf("distinct on fields must lead the orderBy")
+	}
+	for _, v := range s.orderBy[:len(s.distinctOn)] {
+		o, _ := parseOrder(v)
+		found := false
+		for _, field := range s.distinctOn {
+			if o.field == field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("distinct on fields must lead the orderBy, found %s", o.field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package qb
+
+import "testing"
+
+func TestDistinctQuery(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetFields("dept").Distinct()
+	wantQ := "SELECT DISTINCT dept FROM aggemp ORDER BY dept"
+	testQuery(t, b, wantQ, nil)
+	testError(t, b, false)
+
+	b.Reset()
+	b.SetFields("id", "dept").Distinct().OrderBy("dept")
+	wantQ = "SELECT DISTINCT id,dept FROM aggemp ORDER BY dept,id"
+	testQuery(t, b, wantQ, nil)
+
+	b.Reset()
+	b.Distinct().SetLimit(3)
+	wantQ = "SELECT DISTINCT * FROM aggemp ORDER BY id LIMIT 3"
+	testQuery(t, b, wantQ, nil)
+
+	ti, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSQLServerSelect(ti, false).SetFields("dept").Distinct().SetLimit(3)
+	wantQ = "SELECT DISTINCT TOP 3 [dept] FROM [emp] ORDER BY [dept]"
+	testQuery(t, s, wantQ, nil)
+}
+
+func TestDistinctOnQuery(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.DistinctOn("Dept").SetFilter("salary", ">", 10)
+	wantQ := "SELECT DISTINCT ON (dept) * FROM aggemp WHERE salary > $1 ORDER BY dept,id"
+	testQuery(t, b, wantQ, []interface{}{10})
+	testError(t, b, false)
+
+	b.Reset()
+	b.DistinctOn("dept").OrderBy("dept", Desc("salary"))
+	wantQ = "SELECT DISTINCT ON (dept) * FROM aggemp ORDER BY dept,salary DESC,id"
+	testQuery(t, b, wantQ, nil)
+	testError(t, b, false)
+
+	//the last rows is taken by the offset so the highest salary of each dept is still kept.
+	b.SetLimit(2).SetOffset(4)
+	wantQ = "SELECT DISTINCT ON (dept) * FROM aggemp ORDER BY dept,salary DESC,id LIMIT 2 OFFSET 4"
+	if got, _ := b.lastQuery(); got != wantQ {
+		t.Errorf("got query: %s \n             want %s", got, wantQ)
+	}
+}
+
+func TestDistinctOnError(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.DistinctOn("dept").OrderBy(Desc("salary"), "dept")
+	testError(t, b, true)
+
+	b.Reset()
+	b.DistinctOn("notexist")
+	testError(t, b, true)
+
+	b.Reset()
+	b.DistinctOn("dept", "salary").OrderBy("salary", Desc("dept"))
+	testError(t, b, false)
+
+	b.Reset()
+	b.Distinct().ForUpdate()
+	testError(t, b, true)
+
+	ti, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testError(t, NewMySQLSelect(ti, false).DistinctOn("dept"), true)
+}
@@ -0,0 +1,124 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Expr is a raw SQL expression with its own args, each ? on the query is replaced by the placeholder
+//of the dialect numbered after the placeholder before the expression, use ?? for the literal ?.
+//The expression is not checked so it must not be built from the user input.
+type Expr struct {
+	query string
+	args  []interface{}
+	//column is true when the query is applied to the updated field, see Increment.
+	column bool
+}
+
+//NewExpr return an expression of the query with the args.
+//	NewExpr("now() - joindate")
+//	NewExpr("coalesce(nickname, ?)", "anonymous")
+func NewExpr(query string, args ...interface{}) Expr {
+	return Expr{query: query, args: args}
+}
+
+//Func return an expression that call the function name with the args, the name is not quoted.
+//	u.Set("updatedat", Func("now"))
+//	Filter("name", "=", Func("upper", "al"))
+func Func(name string, args ...interface{}) Expr {
+	return NewExpr(name+"("+strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")+")", args...)
+}
+
+//Increment return the value for Update.Set that add n to the updated field,
+//the field is updated by the database so it is safe for the concurrent update.
+//	u.Set("child", Increment(1))
+func Increment(n interface{}) Expr {
+	return Expr{query: "+ ?", args: []interface{}{n}, column: true}
+}
+
+//Decrement return the value for Update.Set that subtract n from the updated field, see Increment.
+func Decrement(n interface{}) Expr {
+	return Expr{query: "- ?", args: []interface{}{n}, column: true}
+}
+
+//build return the query with the placeholder number starting from starting.
+func (e Expr) build(d Dialect, starting int) (query string, args []interface{}, next int) {
+	var b strings.Builder
+	for i := 0; i < len(e.query); i++ {
+		c := e.query[i]
+		if c != '?' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 < len(e.query) && e.query[i+1] == '?' {
+			b.WriteByte('?')
+			i++
+			continue
+		}
+		b.WriteString(d.Placeholder(starting))
+		starting++
+	}
+	return b.String(), e.args, starting
+}
+
+//placeholders return the number of the placeholder on the query.
+func (e Expr) placeholders() int {
+	return strings.Count(e.query, "?") - 2*strings.Count(e.query, "??")
+}
+
+//SetExpr set the expression for the alias, the alias can be used as the field on SetFields,
+//SetFilter, Where, Having and OrderBy. The selected expression is rendered as expr AS alias
+//so it is scanned to the field named after the alias, the filter use the expression
+//and the order use the alias when it is selected.
+//The expression is kept when the select is Reset.
+//	s.SetExpr("lname", NewExpr("lower(name)")).SetFields("id", "lname").SetFilter("lname", "LIKE", "a%")
+func (s *Select) SetExpr(alias string, e Expr) *Select {
+	if s.exprs == nil {
+		s.exprs = make(map[string]Expr)
+	}
+	s.exprs[strings.ToLower(alias)] = e
+	return s
+}
+
+//isExprSelected report whether the expression of the alias is on the select fields.
+func (s *Select) isExprSelected(alias string) bool {
+	for _, field := range s.fields {
+		if scanName(field) == alias {
+			return true
+		}
+	}
+	return false
+}
+
+//exprFilters return the filters where the field that is the alias of the expression is
+//replaced by the expression.
+func (s *Select) exprFilters(filters []filter) []filter {
+	if len(s.exprs) == 0 {
+		return filters
+	}
+	fs := make([]filter, len(filters))
+	for i, f := range filters {
+		if f.isGroup() {
+			f.conds = s.exprFilters(f.conds)
+		} else if e, ok := s.exprs[f.field]; ok {
+			f.expr = &e
+		}
+		fs[i] = f
+	}
+	return fs
+}
+
+func (s *Select) exprError() error {
+	for alias, e := range s.exprs {
+		if e.placeholders() != len(e.args) {
+			return fmt.Errorf("expression %s has %d placeholders but %d args", alias, e.placeholders(), len(e.args))
+		}
+	}
+	for _, v := range s.orderBy {
+		o, _ := parseOrder(v)
+		if e, ok := s.exprs[o.field]; ok && len(e.args) != 0 && !s.isExprSelected(o.field) {
+			return fmt.Errorf("orderBy expression %s with args must be selected", o.field)
+		}
+	}
+	return nil
+}
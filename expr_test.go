@@ -0,0 +1,61 @@
+package qb
+
+import "testing"
+
+func TestExprQuery(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetExpr("lname", NewExpr("lower(dept)")).
+		SetExpr("bonus", NewExpr("salary * ?", 2)).
+		SetExpr("tag", NewExpr("coalesce(dept, ?) || '??'", "none"))
+	b.SetFields("id", "lname", "bonus", "tag AS label").
+		SetFilter("lname", "LIKE", "a%").
+		Where(Filter("bonus", ">", NewExpr("? + ?", 10, 5))).
+		OrderBy(Desc("bonus"))
+	wantQ := "SELECT id,lower(dept) AS lname,salary * $1 AS bonus,coalesce(dept, $2) || '?' AS label FROM aggemp" +
+		" WHERE lower(dept) LIKE $3 AND salary * $4 > ($5 + $6) ORDER BY bonus DESC,id"
+	testQuery(t, b, wantQ, []interface{}{2, "none", "a%", 2, 10, 5})
+	testError(t, b, false)
+
+	b.Reset()
+	b.SetFilter("tag", "=", "x").OrderBy("lname")
+	wantQ = "SELECT * FROM aggemp WHERE coalesce(dept, $1) || '?' = $2 ORDER BY lower(dept),id"
+	testQuery(t, b, wantQ, []interface{}{"none", "x"})
+	testError(t, b, false)
+
+	b.Reset()
+	b.OrderBy("bonus")
+	testError(t, b, true)
+}
+
+func TestExprMySQL(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	b := NewMySQLSelect(emp, false).As("e").Join(dept, "d", On("e.deptid", "d.id"), Filter("d.name", "<>", "hr"))
+	b.SetExpr("label", NewExpr("concat(e.name, ?, d.name)", "@"))
+	b.SetFields("e.id", "label").SetFilter("label", "<>", "")
+	wantQ := "SELECT `e`.`id`,concat(e.name, ?, d.name) AS `label` FROM `emp` AS `e` INNER JOIN `dept` AS `d`" +
+		" ON `e`.`deptid` = `d`.`id` AND `d`.`name` <> ? WHERE concat(e.name, ?, d.name) <> ? ORDER BY `e`.`id`"
+	testQuery(t, b, wantQ, []interface{}{"@", "hr", "@", ""})
+	testError(t, b, false)
+}
+
+func TestExprError(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetExpr("x", NewExpr("salary + ?")).SetFields("x")
+	testError(t, b, true)
+
+	b = newBuilder(t, aggEmp{}, false)
+	b.SetExpr("x", NewExpr("salary + ??", 1)).SetFields("x")
+	testError(t, b, true)
+
+	b = newBuilder(t, aggEmp{}, false)
+	b.SetExpr("x", NewExpr("salary + ?", 1)).SetFields("x", "notexist")
+	testError(t, b, true)
+}
+
+func TestUpdateSetExpr(t *testing.T) {
+	b := newUpdateBuilder(t, aggEmp{})
+	b.Set("salary", NewExpr("salary * ?", 2))
+	b.SetFilter("id", "=", "i8")
+	wantQ := "UPDATE aggemp SET salary = salary * $1 WHERE id = $2"
+	testUpdateQuery(t, b, wantQ, []interface{}{2, "i8"})
+}
@@ -0,0 +1,202 @@
+package qb
+
+import (
+	"errors"
+	"reflect"
+)
+
+//QueryDBExecer is an interface to execute the query and the statement againts database.
+//sql.DB and sql.Tx implement this interface.
+type QueryDBExecer interface {
+	QueryExecer
+	DBExecer
+}
+
+//DefaultFielder is an interface for the Tabler that have the fields with the default value on the database,
+//the zero value of the fields is not inserted by InsertPartial and InsertReturning.
+//Table implement it using qb:"default" or qb:"omitempty" tag.
+type DefaultFielder interface {
+	DefaultFields() []string
+}
+
+//InsertReturning insert src to the database and store the inserted row to dst,
+//dst must be pointer to struct. The default fields with zero value is not inserted
+//so the value is generated by the database, tag the auto increment primary key with qb:"default".
+//For the database that support RETURNING the row is returned by the insert query, otherwise
+//the row is selected by the primary keys where the generated key is taken from LastInsertId.
+func (u *Update) InsertReturning(qe QueryDBExecer, src, dst interface{}) error {
+	values, err := u.getArgs(src)
+	if err != nil {
+		return err
+	}
+	fields, args, generated := u.insertFields(values)
+	if u.d.SupportReturning() {
+		row := qe.QueryRow(u.d.Insert(u.t.TableName(), fields, 1, u.t.Fields()), args...)
+		return scanWithReflection(u.t.Fields(), row, dst)
+	}
+	res, err := qe.Exec(u.d.Insert(u.t.TableName(), fields, 1, nil), args...)
+	if err != nil {
+		return err
+	}
+	pks := u.pkArgs(values)
+	if generated {
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		pks = []interface{}{id}
+	}
+	return NewSelect(u.d, u.t, true).GetByPK(qe, dst, pks...)
+}
+
+//InsertReturningQuery return the query to insert src and return the inserted row,
+//the query doesn't return the row for the database that doesn't support RETURNING.
+//The query is empty when the values can't be taken from src.
+func (u *Update) InsertReturningQuery(src interface{}) (query string, args []interface{}) {
+	values, err := u.getArgs(src)
+	if err != nil {
+		return "", nil
+	}
+	fields, args, _ := u.insertFields(values)
+	var returning []string
+	if u.d.SupportReturning() {
+		returning = u.t.Fields()
+	}
+	return u.d.Insert(u.t.TableName(), fields, 1, returning), args
+}
+
+//InsertPartial insert src to the database where the zero value of the default fields
+//is omitted so the value is set by the database, see DefaultFielder.
+func (u *Update) InsertPartial(dbe DBExecer, src interface{}) error {
+	values, err := u.getArgs(src)
+	if err != nil {
+		return err
+	}
+	fields, args, _ := u.insertFields(values)
+	_, err = dbe.Exec(u.d.Insert(u.t.TableName(), fields, 1, nil), args...)
+	return err
+}
+
+//InsertPartialQuery return the query to insert src where the fields is depend on the value of src,
+//the query is empty when the values can't be taken from src.
+func (u *Update) InsertPartialQuery(src interface{}) (query string, args []interface{}) {
+	values, err := u.getArgs(src)
+	if err != nil {
+		return "", nil
+	}
+	fields, args, _ := u.insertFields(values)
+	return u.d.Insert(u.t.TableName(), fields, 1, nil), args
+}
+
+//insertFields return the fields and the args to insert the values of the table fields,
+//the default fields with zero value is excluded.
+//generated is true when the single primary key is excluded to be generated by the database.
+func (u *Update) insertFields(values []interface{}) (fields []string, args []interface{}, generated bool) {
+	pks := u.t.PrimaryKeys()
+	defaults := make(map[string]bool)
+	if df, ok := u.t.(DefaultFielder); ok {
+		for _, field := range df.DefaultFields() {
+			defaults[field] = true
+		}
+	}
+	for i, field := range u.t.Fields() {
+		if defaults[field] && isZero(values[i]) {
+			generated = generated || len(pks) == 1 && field == pks[0]
+			continue
+		}
+		fields = append(fields, field)
+		args = append(args, values[i])
+	}
+	return fields, args, generated
+}
+
+//pkArgs return the primary keys value from the values of the table fields.
+func (u *Update) pkArgs(values []interface{}) []interface{} {
+	var args []interface{}
+	for _, pk := range u.t.PrimaryKeys() {
+		for i, field := range u.t.Fields() {
+			if field == pk {
+				args = append(args, values[i])
+			}
+		}
+	}
+	return args
+}
+
+//isZero report whether v is nil or the zero value of its type.
+func isZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	return reflect.DeepEqual(v, reflect.Zero(reflect.TypeOf(v)).Interface())
+}
+
+//OnProgress set the function that is called by InsertMany after each chunk is inserted
+//with the number of rows inserted so far and the total rows.
+func (u *Update) OnProgress(fn func(inserted, total int)) *Update {
+	u.progress = fn
+	return u
+}
+
+//InsertMany insert the rows of src using the multi rows insert query, src must be slice of struct
+//or pointer to struct. The rows is inserted in chunks so the args of a query doesn't exceed
+//the limit of the database, use a transaction to insert all or none of the rows.
+//It return the number of rows affected by the inserted chunks.
+func (u *Update) InsertMany(dbe DBExecer, src interface{}) (int64, error) {
+	rv := reflect.ValueOf(src)
+	if rv.Kind() != reflect.Slice {
+		return 0, errors.New("src must be slice")
+	}
+	fields := u.t.Fields()
+	total := rv.Len()
+	size := u.chunkSize(len(fields))
+	var query string
+	var affected int64
+	for start := 0; start < total; start += size {
+		end := start + size
+		if end > total {
+			end = total
+		}
+		//the query is only changed on the last chunk.
+		if query == "" || end-start != size {
+			query = u.d.Insert(u.t.TableName(), fields, end-start, nil)
+		}
+		args := make([]interface{}, 0, (end-start)*len(fields))
+		for i := start; i < end; i++ {
+			values, err := u.getArgs(rv.Index(i).Interface())
+			if err != nil {
+				return affected, err
+			}
+			args = append(args, values...)
+		}
+		res, err := dbe.Exec(query, args...)
+		if err != nil {
+			return affected, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return affected, err
+		}
+		affected += n
+		if u.progress != nil {
+			u.progress(end, total)
+		}
+	}
+	return affected, nil
+}
+
+//chunkSize return the number of rows of an insert query with n fields.
+func (u *Update) chunkSize(n int) int {
+	args, rows := defaultBatchArgs, 0
+	if l, ok := u.d.(batchLimiter); ok {
+		args, rows = l.BatchLimit()
+	}
+	size := args / n
+	if rows > 0 && size > rows {
+		size = rows
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
@@ -0,0 +1,139 @@
+package qb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type insertItem struct {
+	ID   int64 `pk:"1" qb:"default"`
+	Name string
+	Qty  int
+}
+
+type partialItem struct {
+	ID        int64 `pk:"1" qb:"default"`
+	Name      string
+	Status    string    `qb:"default"`
+	Note      *string   `qb:"omitempty"`
+	CreatedAt time.Time `qb:"default,omitempty"`
+}
+
+func TestInsertPartialQuery(t *testing.T) {
+	tbl, err := NewTable("item", partialItem{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"id", "status", "note", "createdat"}; !reflect.DeepEqual(tbl.DefaultFields(), want) {
+		t.Errorf("got: %v want %v", tbl.DefaultFields(), want)
+	}
+	note := ""
+	at := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		src   interface{}
+		wantQ string
+		wantA []interface{}
+	}{
+		{partialItem{Name: "a"}, "INSERT INTO item (name) VALUES ($1)", []interface{}{"a"}},
+		{partialItem{ID: 3, Status: "new", Note: &note},
+			"INSERT INTO item (id,name,status,note) VALUES ($1,$2,$3,$4)", []interface{}{int64(3), "", "new", &note}},
+		{&partialItem{Name: "b", CreatedAt: at},
+			"INSERT INTO item (name,createdat) VALUES ($1,$2)", []interface{}{"b", at}},
+	}
+	u := NewPQUpdate(tbl)
+	for _, tt := range tests {
+		gotQ, gotA := u.InsertPartialQuery(tt.src)
+		if gotQ != tt.wantQ {
+			t.Errorf("got: %s\n        want %s", gotQ, tt.wantQ)
+		}
+		if !reflect.DeepEqual(gotA, tt.wantA) {
+			t.Errorf("got: %v want %v", gotA, tt.wantA)
+		}
+	}
+	//the field that is not tagged is inserted even when it is zero.
+	type untagged struct {
+		ID   int64  `pk:"1"`
+		Name string `qb:"default"`
+	}
+	utbl, err := NewTable("item", untagged{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotQ, gotA := NewPQUpdate(utbl).InsertPartialQuery(untagged{})
+	if wantQ := "INSERT INTO item (id) VALUES ($1)"; gotQ != wantQ {
+		t.Errorf("got: %s want %s", gotQ, wantQ)
+	}
+	if !reflect.DeepEqual(gotA, []interface{}{int64(0)}) {
+		t.Errorf("got: %v want [0]", gotA)
+	}
+	//InsertQuery still insert all the fields.
+	if got, want := u.InsertQuery(), "INSERT INTO item (id,name,status,note,createdat) VALUES ($1,$2,$3,$4,$5)"; got != want {
+		t.Errorf("got: %s want %s", got, want)
+	}
+}
+
+func TestInsertReturningQuery(t *testing.T) {
+	tbl, err := NewTable("item", insertItem{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		d     Dialect
+		src   interface{}
+		wantQ string
+		wantA []interface{}
+	}{
+		{PQ{}, insertItem{Name: "a", Qty: 2},
+			"INSERT INTO item (name,qty) VALUES ($1,$2) RETURNING id,name,qty", []interface{}{"a", 2}},
+		{PQ{}, &insertItem{ID: 7, Name: "a"},
+			"INSERT INTO item (id,name,qty) VALUES ($1,$2,$3) RETURNING id,name,qty", []interface{}{int64(7), "a", 0}},
+		{SQLServer{}, insertItem{Name: "a", Qty: 2},
+			"INSERT INTO [item] ([name],[qty]) OUTPUT INSERTED.[id],INSERTED.[name],INSERTED.[qty] VALUES (@p1,@p2)",
+			[]interface{}{"a", 2}},
+		{MySQL{}, insertItem{Name: "a", Qty: 2},
+			"INSERT INTO `item` (`name`,`qty`) VALUES (?,?)", []interface{}{"a", 2}},
+	}
+	for _, tt := range tests {
+		gotQ, gotA := NewUpdate(tt.d, tbl).InsertReturningQuery(tt.src)
+		if gotQ != tt.wantQ {
+			t.Errorf("got: %s\n        want %s", gotQ, tt.wantQ)
+		}
+		if !reflect.DeepEqual(gotA, tt.wantA) {
+			t.Errorf("got: %v want %v", gotA, tt.wantA)
+		}
+	}
+}
+
+//smallBatch limit the args so the rows is inserted in the small chunks.
+type smallBatch struct {
+	SQLite
+}
+
+func (smallBatch) BatchLimit() (args, rows int) {
+	return 7, 0
+}
+
+func TestChunkSize(t *testing.T) {
+	tbl, err := NewTable("item", insertItem{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		d    Dialect
+		n    int
+		want int
+	}{
+		{PQ{}, 3, 21845},
+		{SQLite{}, 3, 10922},
+		{SQLServer{}, 3, 700},
+		{SQLServer{}, 2, 1000},
+		{smallBatch{}, 3, 2},
+		{smallBatch{}, 10, 1},
+	}
+	for _, tt := range tests {
+		if got := NewUpdate(tt.d, tbl).chunkSize(tt.n); got != tt.want {
+			t.Errorf("%T got chunk size: %d want %d", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package qb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Col is a reference to a field that can be used as the filter value to compare two fields,
+//the field can be qualified with the table name or alias.
+//	Filter("emp.deptid", "=", Col("d.id"))
+type Col string
+
+//On return a condition that is true when the left field is equal to the right field,
+//it is a shortcut for Filter(left, "=", Col(right)).
+func On(left, right string) Condition {
+	return Filter(left, "=", Col(right))
+}
+
+const (
+	innerJoin = "INNER JOIN"
+	leftJoin  = "LEFT JOIN"
+)
+
+type join struct {
+	kind  string
+	t     Tabler
+	alias string
+	on    []filter
+}
+
+//qualifier return the name used to refer the fields of the joined table.
+func (j join) qualifier() string {
+	if j.alias != "" {
+		return j.alias
+	}
+	return j.t.TableName()
+}
+
+//As set the alias for the table of the select, the alias is used to qualify the fields
+//when the select has a join.
+func (s *Select) As(alias string) *Select {
+	s.alias = strings.ToLower(alias)
+	return s
+}
+
+//Join add INNER JOIN of t with the on conditions, if the alias is not empty
+//the fields of t is referred using the alias instead of the table name.
+//	s.As("e").Join(dept, "d", On("e.deptid", "d.id"))
+func (s *Select) Join(t Tabler, alias string, on ...Condition) *Select {
+	return s.join(innerJoin, t, alias, on)
+}
+
+//LeftJoin add LEFT JOIN of t with the on conditions, see Join.
+func (s *Select) LeftJoin(t Tabler, alias string, on ...Condition) *Select {
+	return s.join(leftJoin, t, alias, on)
+}
+
+func (s *Select) join(kind string, t Tabler, alias string, on []Condition) *Select {
+	j := join{kind: kind, t: t, alias: strings.ToLower(alias)}
+	for _, cond := range on {
+		j.on = append(j.on, asFilter(cond))
+	}
+	s.joins = append(s.joins, j)
+	return s
+}
+
+//qualifier return the name used to refer the fields of the select table.
+func (s *Select) qualifier() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.t.TableName()
+}
+
+//qualify return the field qualified with the select table when the select has a join.
+func (s *Select) qualify(fields []string) []string {
+	if len(s.joins) == 0 {
+		return fields
+	}
+	q := make([]string, len(fields))
+	for i, field := range fields {
+		q[i] = s.qualifier() + "." + field
+	}
+	return q
+}
+
+//tableFields return the fields of the table and the joined table,
+//the fields is qualified when the select has a join.
+func (s *Select) tableFields() []string {
+	fields := s.qualify(s.t.Fields())
+	for _, j := range s.joins {
+		for _, field := range j.t.Fields() {
+			fields = append(fields, j.qualifier()+"."+field)
+		}
+	}
+	return fields
+}
+
+//fromQuery return the FROM clause with the joins.
+func (s *Select) fromQuery(starting int) (query string, args []interface{}, next int) {
+	query, args, starting = tableQuery(s.d, s.t, s.alias, starting)
+	query = " FROM " + query
+	for _, j := range s.joins {
+		table, tableArgs, next := tableQuery(s.d, j.t, j.alias, starting)
+		query += " " + j.kind + " " + table
+		args, starting = append(args, tableArgs...), next
+		if len(j.on) == 0 {
+			continue
+		}
+		w := make([]string, len(j.on))
+		for i, cond := range j.on {
+			var condArgs []interface{}
+			w[i], condArgs, starting = cond.build(s.d, starting)
+			args = append(args, condArgs...)
+		}
+		query += " ON " + strings.Join(w, " AND ")
+	}
+	return query, args, starting
+}
+
+func (s *Select) joinError() error {
+	for _, j := range s.joins {
+		if err := derivedError(j.t); err != nil {
+			return err
+		}
+		if len(j.on) == 0 {
+			return fmt.Errorf("join %s doesn't have on condition", j.qualifier())
+		}
+		for _, cond := range j.on {
+			if err := s.checkFilter(cond); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//tablerOf return the tabler that is referred by qualifier.
+func (s *Select) tablerOf(qualifier string) (Tabler, error) {
+	if qualifier == s.qualifier() {
+		return s.t, nil
+	}
+	for _, j := range s.joins {
+		if qualifier == j.qualifier() {
+			return j.t, nil
+		}
+	}
+	return nil, errors.New("table " + qualifier + " doesn't exist")
+}
+
+//splitQualifier split the qualified field into the qualifier and the field name.
+func splitQualifier(field string) (qualifier, name string) {
+	i := strings.LastIndex(field, ".")
+	if i < 0 {
+		return "", field
+	}
+	return field[:i], field[i+1:]
+}
+
+//splitAlias split "field AS alias" into the field and the alias.
+func splitAlias(field string) (name, alias string) {
+	words := strings.Fields(field)
+	if len(words) == 3 && strings.ToUpper(words[1]) == "AS" {
+		return words[0], words[2]
+	}
+	return field, ""
+}
+
+//scanName return the name of the column for the selected field,
+//it is the alias when the field has an alias.
+func scanName(field string) string {
+	name, alias := splitAlias(field)
+	if alias != "" {
+		return alias
+	}
+	return name
+}
@@ -0,0 +1,86 @@
+package qb
+
+import "testing"
+
+type joinEmp struct {
+	ID     string `pk:"1"`
+	Name   string
+	DeptID string
+}
+
+type joinDept struct {
+	ID   string `pk:"1"`
+	Name string
+}
+
+func newJoinBuilder(t *testing.T, explicit bool) *Select {
+	emp, err := NewTable("emp", joinEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	dept, err := NewTable("dept", joinDept{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	return NewPQSelect(emp, explicit).As("e").Join(dept, "d", On("e.deptid", "d.id"))
+}
+
+func TestJoinQuery(t *testing.T) {
+	b := newJoinBuilder(t, true)
+	wantQ := "SELECT e.id,e.name,e.deptid,d.id,d.name FROM emp AS e INNER JOIN dept AS d ON e.deptid = d.id" +
+		" ORDER BY e.id"
+	testQuery(t, b, wantQ, nil)
+	testError(t, b, false)
+
+	b.Reset()
+	b.SetFields("e.name", "d.name AS deptname").SetFilter("d.name", "=", "it").SetLimit(5)
+	wantQ = "SELECT e.name,d.name AS deptname FROM emp AS e INNER JOIN dept AS d ON e.deptid = d.id" +
+		" WHERE d.name = $1 ORDER BY e.id LIMIT 5"
+	testQuery(t, b, wantQ, []interface{}{"it"})
+	testError(t, b, false)
+
+	wantQ = "SELECT e.name,d.name AS deptname FROM emp AS e INNER JOIN dept AS d ON e.deptid = d.id WHERE e.id = $1"
+	if got := b.SelectByPK(); got != wantQ {
+		t.Errorf("got query: %s \n             want %s", got, wantQ)
+	}
+}
+
+func TestLeftJoinQuery(t *testing.T) {
+	emp, err := NewTable("emp", joinEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	dept, err := NewTable("dept", joinDept{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	b := NewMySQLSelect(emp, false)
+	b.LeftJoin(dept, "", On("emp.deptid", "dept.id"), Filter("dept.name", "<>", "hr"))
+	b.SetFilter("emp.name", "=", "al")
+	wantQ := "SELECT * FROM `emp` LEFT JOIN `dept` ON `emp`.`deptid` = `dept`.`id` AND `dept`.`name` <> ?" +
+		" WHERE `emp`.`name` = ? ORDER BY `emp`.`id`"
+	testQuery(t, b, wantQ, []interface{}{"hr", "al"})
+	testError(t, b, false)
+}
+
+func TestJoinError(t *testing.T) {
+	b := newJoinBuilder(t, false)
+	b.SetFields("d.deptid")
+	testError(t, b, true)
+	b.Reset()
+	b.SetFields("x.name")
+	testError(t, b, true)
+	b.Reset()
+	b.SetFilter("e.deptid", "=", Col("d.notexist"))
+	testError(t, b, true)
+	b.Reset()
+	b.OrderBy(Desc("d.name"))
+	testError(t, b, false)
+
+	emp, _ := NewTable("emp", joinEmp{})
+	dept, _ := NewTable("dept", joinDept{})
+	b = NewPQSelect(emp, false).Join(dept, "d")
+	testError(t, b, true)
+	b = NewPQSelect(emp, false).Join(dept, "d", On("emp.deptid", "d.notexist"))
+	testError(t, b, true)
+}
@@ -76,7 +76,11 @@ func (l *List) GetAll(qe QueryExecer, dstx interface{}) error {
 	i := 0
 	var err error
 	for l.rows.Next() {
-		if err = scanReflectValue(l.s.fields, l.rows, v); err != nil {
+		if kind == reflect.Struct {
+			//zeroes the previous row so the pointer fields is not shared between the rows.
+			v.Elem().Set(reflect.Zero(v.Elem().Type()))
+		}
+		if err = scanReflectValue(l.s.scanFields(), l.rows, v); err != nil {
 			return err
 		}
 		if i < n {
@@ -91,12 +95,14 @@ func (l *List) GetAll(qe QueryExecer, dstx interface{}) error {
 	if err != nil {
 		return err
 	}
-	dst.SetLen(i)
-	vo.Elem().Set(dst)
+	vo.Elem().Set(dst.Slice(0, i))
 	return nil
 }
 
 func (l *List) GetNext(qe QueryExecer, cursor string) error {
+	if err := l.s.lockError(); err != nil {
+		return err
+	}
 	err := l.s.setCursor(cursor)
 	if err != nil {
 		return err
@@ -115,7 +121,7 @@ func (l *List) GetLast(qe QueryExecer, cursor string) error {
 	if err := l.s.getLast(qe, cursor); err != nil {
 		return err
 	}
-	query, args := l.s.Query()
+	query, args := l.s.lastQuery()
 	rows, err := qe.Query(query, args...)
 	if err != nil {
 		return err
@@ -135,7 +141,7 @@ func (l *List) Next(dst interface{}) error {
 		}
 		// return l.scanWithReflect(dst)
 		v := reflect.ValueOf(dst)
-		if err := scanReflectValue(l.s.fields, l.rows, v); err != nil {
+		if err := scanReflectValue(l.s.scanFields(), l.rows, v); err != nil {
 			// if err := scanWithReflection(l.s.fields, l.rows, dst); err != nil {
 			l.rows.Close()
 			return err
@@ -212,24 +218,33 @@ func scanReflectValue(fields []string, r rowScanner, v reflect.Value) error {
 		return errors.New("dst must be pointer to struct")
 	}
 	ve := v.Elem()
-	n := ve.NumField()
-	if n <= 0 || n < len(fields) {
+	if ve.Kind() != reflect.Struct || ve.NumField() <= 0 {
 		return errors.New("destination field not enough")
 	}
 
 	var args []interface{}
 	for _, field := range fields {
-		dstF := ve.FieldByNameFunc(func(dstName string) bool {
-			if strings.ToLower(dstName) == field {
-				return true
-			}
-			return false
-		})
+		dstF := fieldByName(ve, scanName(field))
+		if !dstF.IsValid() {
+			return fmt.Errorf("field %s doesn't exist on dst", field)
+		}
 		if dstS, ok := dstF.Interface().(sql.Scanner); ok {
 			args = append(args, dstS)
 			continue
 		}
+		//sql.NullString and the like implement Scanner on the pointer.
+		if dstF.CanAddr() {
+			if dstS, ok := dstF.Addr().Interface().(sql.Scanner); ok {
+				args = append(args, dstS)
+				continue
+			}
+		}
 		if dstF.Kind() == reflect.Ptr {
+			//the pointer to the pointer is set to nil on NULL and allocated otherwise.
+			if dstF.CanAddr() {
+				args = append(args, dstF.Addr().Interface())
+				continue
+			}
 			args = append(args, dstF.Interface())
 			continue
 		}
@@ -246,6 +261,41 @@ func scanReflectValue(fields []string, r rowScanner, v reflect.Value) error {
 	return nil
 }
 
+//fieldByName return the struct field of v for the field, the field qualified with
+//the table name or alias is stored on the nested struct named after the qualifier,
+//or the field prefixed with the qualifier, e.g. d.name is stored on D.Name, DName or D_Name.
+func fieldByName(v reflect.Value, field string) reflect.Value {
+	qualifier, name := splitQualifier(field)
+	if qualifier == "" {
+		return structField(v, name)
+	}
+	nested := structField(v, qualifier)
+	if nested.Kind() == reflect.Ptr && nested.Type().Elem().Kind() == reflect.Struct {
+		if nested.IsNil() && nested.CanSet() {
+			nested.Set(reflect.New(nested.Type().Elem()))
+		}
+		nested = nested.Elem()
+	}
+	if nested.Kind() == reflect.Struct {
+		if dstF := structField(nested, name); dstF.IsValid() {
+			return dstF
+		}
+	}
+	if dstF := structField(v, qualifier+name); dstF.IsValid() {
+		return dstF
+	}
+	if dstF := structField(v, qualifier+"_"+name); dstF.IsValid() {
+		return dstF
+	}
+	return structField(v, name)
+}
+
+func structField(v reflect.Value, name string) reflect.Value {
+	return v.FieldByNameFunc(func(dstName string) bool {
+		return strings.ToLower(dstName) == name
+	})
+}
+
 type fieldScanner struct {
 	dv reflect.Value
 }
@@ -256,6 +306,11 @@ func (sc fieldScanner) Scan(src interface{}) error {
 	if !sc.dv.CanSet() {
 		return errors.New("field is not settable")
 	}
+	if src == nil {
+		//NULL, e.g. from the unmatched row of LEFT JOIN, leave the field zero.
+		sc.dv.Set(reflect.Zero(sc.dv.Type()))
+		return nil
+	}
 	switch sc.dv.Kind() {
 	case reflect.String:
 		s := asString(src)
@@ -287,6 +342,10 @@ func (sc fieldScanner) Scan(src interface{}) error {
 		return nil
 	case reflect.Struct:
 		if _, ok := sc.dv.Interface().(time.Time); ok {
+			if t, ok := src.(time.Time); ok {
+				sc.dv.Set(reflect.ValueOf(t.UTC()))
+				return nil
+			}
 			s := asString(src)
 			dtt, err := time.Parse(pqTime, s)
 			sc.dv.Set(reflect.ValueOf(dtt))
@@ -0,0 +1,67 @@
+package qb
+
+import "fmt"
+
+const (
+	lockUpdate = "UPDATE"
+	lockShare  = "SHARE"
+	skipLocked = "SKIP LOCKED"
+	noWait     = "NOWAIT"
+)
+
+//ForUpdate lock the selected rows for update until the end of the transaction,
+//the lock is rendered after LIMIT and OFFSET.
+//The lock is not supported by SQLite and SQL Server, the query return an error on those dialect.
+//	s.SetFilter("status", "=", "new").SetLimit(10).ForUpdate().SkipLocked()
+func (s *Select) ForUpdate() *Select {
+	s.lock = lockUpdate
+	return s
+}
+
+//ForShare lock the selected rows so it can't be updated by the other transaction.
+func (s *Select) ForShare() *Select {
+	s.lock = lockShare
+	return s
+}
+
+//SkipLocked skip the rows that is locked by the other transaction instead of waiting,
+//it is used with ForUpdate or ForShare.
+func (s *Select) SkipLocked() *Select {
+	s.wait = skipLocked
+	return s
+}
+
+//NoWait return an error when the rows is locked by the other transaction instead of waiting,
+//it is used with ForUpdate or ForShare.
+func (s *Select) NoWait() *Select {
+	s.wait = noWait
+	return s
+}
+
+//lockQuery return the locking clause, the standard clause is rendered for the dialect
+//that doesn't support the lock so the query fail instead of running without the lock.
+func (s *Select) lockQuery() string {
+	if s.lock == "" {
+		return ""
+	}
+	if l, ok := s.d.(locker); ok {
+		return l.Lock(s.lock, s.wait)
+	}
+	return lockQuery(s.lock, s.wait)
+}
+
+func (s *Select) lockError() error {
+	if s.lock == "" {
+		if s.wait != "" {
+			return fmt.Errorf("%s need ForUpdate or ForShare", s.wait)
+		}
+		return nil
+	}
+	if _, ok := s.d.(locker); !ok {
+		return fmt.Errorf("FOR %s is not supported by the dialect", s.lock)
+	}
+	if len(s.groupBy) != 0 || len(s.compound) != 0 || s.distinct || len(s.distinctOn) != 0 {
+		return fmt.Errorf("FOR %s can't be used with group by, distinct or compound query", s.lock)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package qb
+
+import "testing"
+
+func TestLockQuery(t *testing.T) {
+	b := newBuilder(t, aggEmp{}, false)
+	b.SetFilter("dept", "=", "it").SetLimit(5).ForUpdate().SkipLocked()
+	wantQ := "SELECT * FROM aggemp WHERE dept = $1 ORDER BY id LIMIT 5 FOR UPDATE SKIP LOCKED"
+	testQuery(t, b, wantQ, []interface{}{"it"})
+	testError(t, b, false)
+
+	wantQ = "SELECT * FROM aggemp WHERE id = $1 FOR UPDATE SKIP LOCKED"
+	if got := b.SelectByPK(); got != wantQ {
+		t.Errorf("got query: %s \n             want %s", got, wantQ)
+	}
+
+	b.ForShare().NoWait()
+	wantQ = "SELECT * FROM aggemp WHERE dept = $1 ORDER BY id LIMIT 5 FOR SHARE NOWAIT"
+	testQuery(t, b, wantQ, []interface{}{"it"})
+
+	b.Reset()
+	wantQ = "SELECT * FROM aggemp ORDER BY id"
+	testQuery(t, b, wantQ, nil)
+
+	ti, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMySQLSelect(ti, false).SetLimit(1).SetOffset(2).ForUpdate()
+	wantQ = "SELECT * FROM `emp` ORDER BY `id` LIMIT 2, 1 FOR UPDATE"
+	testQuery(t, m, wantQ, nil)
+}
+
+func TestLockError(t *testing.T) {
+	ti, err := NewTable("emp", aggEmp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testError(t, NewSQLiteSelect(ti, false).ForUpdate(), true)
+	testError(t, NewSQLServerSelect(ti, false).ForShare(), true)
+	testError(t, NewPQSelect(ti, false).SkipLocked(), true)
+	testError(t, NewPQSelect(ti, false).SetFields("dept").GroupBy("dept").ForUpdate(), true)
+	testError(t, NewPQSelect(ti, false).ForUpdate().NoWait(), false)
+
+	//every execution fail before the query is run without the lock.
+	s := NewSQLiteSelect(ti, false).ForUpdate()
+	var dst aggEmp
+	l := NewList(s)
+	errs := []error{
+		s.GetByPK(nil, &dst, "a"),
+		s.GetNext(nil, &dst, ""),
+		s.GetPrevious(nil, &dst, ""),
+		s.GetLast(nil, &dst, ""),
+		l.Get(nil),
+		l.GetAll(nil, &[]aggEmp{}),
+		l.GetNext(nil, ""),
+		l.GetLast(nil, ""),
+	}
+	_, err = s.Get(nil)
+	errs = append(errs, err)
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("%d got: nil want an error", i)
+		}
+	}
+	testQuery(t, s, `SELECT * FROM "emp" ORDER BY "id" FOR UPDATE`, nil)
+}
@@ -0,0 +1,88 @@
+package qb
+
+import (
+	"strconv"
+	"strings"
+)
+
+//maxMySQLLimit is the limit used when only the offset is specified,
+//MySQL doesn't support OFFSET without LIMIT.
+const maxMySQLLimit = "18446744073709551615"
+
+//MySQL is a Dialect for MySQL and MariaDB database.
+type MySQL struct{}
+
+//Placeholder return ? placeholder.
+func (MySQL) Placeholder(n int) string {
+	return "?"
+}
+
+//Quote quote the identifier with backtick.
+func (MySQL) Quote(identifier string) string {
+	return quoteIdentifier(identifier, "`", "`")
+}
+
+//LimitOffset return LIMIT offset, count clause.
+func (MySQL) LimitOffset(limit, offset int) string {
+	switch {
+	case limit > 0 && offset > 0:
+		return " LIMIT " + strconv.Itoa(offset) + ", " + strconv.Itoa(limit)
+	case limit > 0:
+		return " LIMIT " + strconv.Itoa(limit)
+	case offset > 0:
+		return " LIMIT " + strconv.Itoa(offset) + ", " + maxMySQLLimit
+	}
+	return ""
+}
+
+//Insert return an insert query, returning is ignored as MySQL doesn't support it.
+func (d MySQL) Insert(table string, fields []string, rows int, returning []string) string {
+	return insertQuery(d, table, fields, rows)
+}
+
+//SupportReturning return false.
+func (MySQL) SupportReturning() bool {
+	return false
+}
+
+//Upsert return INSERT ... ON DUPLICATE KEY UPDATE query, MySQL use the primary and unique keys
+//of the table to find the conflict so conflict is only used when update is empty
+//to assign the first conflict field, or the first field when conflict is empty, to itself.
+func (d MySQL) Upsert(table string, fields []string, rows int, conflict, update []string) string {
+	query := insertQuery(d, table, fields, rows) + " ON DUPLICATE KEY UPDATE "
+	if len(update) == 0 {
+		field := fields[0]
+		if len(conflict) != 0 {
+			field = conflict[0]
+		}
+		field = d.Quote(field)
+		return query + field + " = " + field
+	}
+	set := make([]string, len(update))
+	for i, field := range update {
+		set[i] = d.Quote(field) + " = VALUES(" + d.Quote(field) + ")"
+	}
+	return query + strings.Join(set, ",")
+}
+
+//Like return LIKE query where ILIKE compare the lower case of the field and the pattern,
+//MySQL use backslash as the default escape character.
+func (MySQL) Like(field, op, placeholder string) string {
+	return likeQuery(field, op, placeholder, "", false)
+}
+
+//Order return field [DESC], NULLS FIRST and NULLS LAST is emulated
+//as MySQL doesn't support it.
+func (MySQL) Order(field string, desc bool, nulls string) string {
+	return nullsOrder(field, desc, nulls)
+}
+
+//Lock return FOR UPDATE|SHARE [SKIP LOCKED|NOWAIT] clause, it need MySQL 8.0 or later.
+func (MySQL) Lock(strength, wait string) string {
+	return lockQuery(strength, wait)
+}
+
+//BatchLimit return 65535 args of the prepared statement.
+func (MySQL) BatchLimit() (args, rows int) {
+	return 65535, 0
+}
@@ -0,0 +1,95 @@
+package qb
+
+import "testing"
+
+func TestMySQLSelectQuery(t *testing.T) {
+	type Emp struct {
+		ID   string `pk:"1"`
+		Name string
+		Age  int
+	}
+	tbl, err := NewTable("", Emp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	b := NewMySQLSelect(tbl, true)
+	b.SetRange("Name", "a", "b")
+	wantQ := "SELECT `id`,`name`,`age` FROM `emp` WHERE `name` >= ? AND `name` <= ? ORDER BY `id`"
+	wantA := []interface{}{"a", "b"}
+	testQuery(t, b, wantQ, wantA)
+	b.SetLimit(5)
+	testQuery(t, b, wantQ+" LIMIT 5", wantA)
+	b.SetOffset(10)
+	testQuery(t, b, wantQ+" LIMIT 10, 5", wantA)
+	b.SetLimit(0)
+	testQuery(t, b, wantQ+" LIMIT 10, 18446744073709551615", wantA)
+
+	got := b.SelectByPK()
+	want := "SELECT `id`,`name`,`age` FROM `emp` WHERE `id` = ?"
+	if got != want {
+		t.Errorf("got query: %s \n             want %s", got, want)
+	}
+}
+
+func TestMySQLUpdateQuery(t *testing.T) {
+	type Emp struct {
+		ID   string `pk:"1"`
+		Name string
+		Age  int
+	}
+	tbl, err := NewTable("", Emp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	b := NewMySQLUpdate(tbl)
+	b.Set("name", "al")
+	b.SetFilter("id", "=", "i8")
+	wantQ := "UPDATE `emp` SET `name` = ? WHERE `id` = ?"
+	wantA := []interface{}{"al", "i8"}
+	testUpdateQuery(t, b, wantQ, wantA)
+
+	got := b.InsertQuery()
+	want := "INSERT INTO `emp` (`id`,`name`,`age`) VALUES (?,?,?)"
+	if got != want {
+		t.Errorf("got: %s want %s", got, want)
+	}
+}
+
+func TestMySQLUpsert(t *testing.T) {
+	d := MySQL{}
+	var tests = []struct {
+		update []string
+		want   string
+	}{
+		{nil, "INSERT INTO `emp` (`id`,`name`) VALUES (?,?) ON DUPLICATE KEY UPDATE `id` = `id`"},
+		{[]string{"name"},
+			"INSERT INTO `emp` (`id`,`name`) VALUES (?,?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)"},
+	}
+	for _, test := range tests {
+		got := d.Upsert("emp", []string{"id", "name"}, 1, []string{"id"}, test.update)
+		if got != test.want {
+			t.Errorf("got: %s\n        want %s", got, test.want)
+		}
+	}
+	got := d.Upsert("emp", []string{"name", "id"}, 1, nil, nil)
+	want := "INSERT INTO `emp` (`name`,`id`) VALUES (?,?) ON DUPLICATE KEY UPDATE `name` = `name`"
+	if got != want {
+		t.Errorf("got: %s\n        want %s", got, want)
+	}
+}
+
+func TestMySQLQuote(t *testing.T) {
+	var tests = []struct {
+		in, want string
+	}{
+		{"id", "`id`"},
+		{"emp.id", "`emp`.`id`"},
+		{"emp.*", "`emp`.*"},
+		{"we`ird", "`we``ird`"},
+	}
+	for _, test := range tests {
+		if got := (MySQL{}).Quote(test.in); got != test.want {
+			t.Errorf("got: %s want %s", got, test.want)
+		}
+	}
+}
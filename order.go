@@ -0,0 +1,115 @@
+package qb
+
+import (
+	"errors"
+	"strings"
+)
+
+//Asc return the ascending order of the field to use in Select.OrderBy.
+func Asc(field string) string {
+	return field + " ASC"
+}
+
+//Desc return the descending order of the field to use in Select.OrderBy.
+//	OrderBy(Desc("joindate"), Asc("id"))
+func Desc(field string) string {
+	return field + " DESC"
+}
+
+//NullsFirst return the order that place the null values before the other values.
+//	OrderBy(NullsFirst(Desc("joindate")))
+func NullsFirst(order string) string {
+	return order + " NULLS FIRST"
+}
+
+//NullsLast return the order that place the null values after the other values.
+func NullsLast(order string) string {
+	return order + " NULLS LAST"
+}
+
+const (
+	nullsFirst = "FIRST"
+	nullsLast  = "LAST"
+)
+
+//order is the parsed form of "field [ASC|DESC] [NULLS FIRST|NULLS LAST]".
+type order struct {
+	field string
+	desc  bool
+	nulls string
+}
+
+func parseOrder(s string) (o order, err error) {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return o, errors.New("orderBy field is empty")
+	}
+	o.field = strings.ToLower(words[0])
+	rest := strings.ToUpper(strings.Join(words[1:], " "))
+	switch {
+	case strings.HasPrefix(rest, "ASC"):
+		rest = strings.TrimPrefix(rest, "ASC")
+	case strings.HasPrefix(rest, "DESC"):
+		o.desc = true
+		rest = strings.TrimPrefix(rest, "DESC")
+	}
+	switch strings.TrimSpace(rest) {
+	case "":
+	case "NULLS FIRST":
+		o.nulls = nullsFirst
+	case "NULLS LAST":
+		o.nulls = nullsLast
+	default:
+		return order{field: o.field}, errors.New("orderBy " + s + " is invalid")
+	}
+	return o, nil
+}
+
+//invert return the order that sort the rows in reverse.
+func (o order) invert() order {
+	o.desc = !o.desc
+	switch o.nulls {
+	case nullsFirst:
+		o.nulls = nullsLast
+	case nullsLast:
+		o.nulls = nullsFirst
+	}
+	return o
+}
+
+//orderer is implemented by the Dialect that doesn't support the standard NULLS FIRST and NULLS LAST.
+type orderer interface {
+	//Order return the ORDER BY item for the quoted field, nulls is empty, FIRST or LAST.
+	Order(field string, desc bool, nulls string) string
+}
+
+//orderItem return the ORDER BY item of the field using the dialect d,
+//by default it is field [DESC] [NULLS FIRST|NULLS LAST].
+func orderItem(d Dialect, field string, desc bool, nulls string) string {
+	if o, ok := d.(orderer); ok {
+		return o.Order(field, desc, nulls)
+	}
+	if desc {
+		field += " DESC"
+	}
+	if nulls != "" {
+		field += " NULLS " + nulls
+	}
+	return field
+}
+
+//nullsOrder return the order for the database that doesn't support NULLS FIRST and NULLS LAST
+//by ordering the rows with a null flag before the field.
+func nullsOrder(field string, desc bool, nulls string) string {
+	query := field
+	if desc {
+		query += " DESC"
+	}
+	switch nulls {
+	case nullsFirst:
+		return "CASE WHEN " + field + " IS NULL THEN 0 ELSE 1 END," + query
+	case nullsLast:
+		return "CASE WHEN " + field + " IS NULL THEN 1 ELSE 0 END," + query
+	}
+	return query
+}
@@ -0,0 +1,67 @@
+package qb
+
+import "testing"
+
+func TestOrderByDirection(t *testing.T) {
+	type Emp struct {
+		ID       string `pk:"1"`
+		Name     string
+		JoinDate string
+	}
+	tbl, err := NewTable("", Emp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	var tests = []struct {
+		d     Dialect
+		wantQ string
+	}{
+		{PQ{}, "SELECT * FROM emp ORDER BY joindate DESC NULLS LAST,name,id DESC"},
+		{SQLite{}, `SELECT * FROM "emp" ORDER BY "joindate" DESC NULLS LAST,"name","id" DESC`},
+		{MySQL{}, "SELECT * FROM `emp` ORDER BY CASE WHEN `joindate` IS NULL THEN 1 ELSE 0 END,`joindate` DESC," +
+			"`name`,`id` DESC"},
+	}
+	for _, test := range tests {
+		b := NewSelect(test.d, tbl, false)
+		b.OrderBy(NullsLast(Desc("JoinDate")), Asc("name"), "id desc")
+		testError(t, b, false)
+		testQuery(t, b, test.wantQ, nil)
+	}
+
+	b := NewPQSelect(tbl, false)
+	b.OrderBy(Desc("name"))
+	testQuery(t, b, "SELECT * FROM emp ORDER BY name DESC,id", nil)
+	b.Reset()
+	b.OrderBy("name DESC NULLS")
+	testError(t, b, true)
+	b.Reset()
+	b.OrderBy(Desc("FieldNotExist"))
+	testError(t, b, true)
+}
+
+func TestLastQuery(t *testing.T) {
+	type Emp struct {
+		ID       string `pk:"1"`
+		Name     string
+		JoinDate string
+	}
+	b := newBuilder(t, Emp{}, true)
+	b.OrderBy(NullsFirst(Desc("joindate"))).SetFilter("name", ">", "a").SetLimit(2).SetOffset(10)
+	gotQ, gotA := b.lastQuery()
+	wantQ := "SELECT * FROM (SELECT id,name,joindate FROM emp WHERE name > $1" +
+		" ORDER BY joindate NULLS LAST,id DESC LIMIT 2) AS xxlast ORDER BY joindate DESC NULLS FIRST,id"
+	if gotQ != wantQ {
+		t.Errorf("got query: %s \n             want %s", gotQ, wantQ)
+	}
+	if len(gotA) != 1 || gotA[0] != "a" {
+		t.Errorf("got args: %v want [a]", gotA)
+	}
+
+	b.Reset()
+	b.SetFields("id").OrderBy("name").SetLimit(2).SetOffset(10)
+	gotQ, _ = b.lastQuery()
+	wantQ = "SELECT id FROM emp ORDER BY name,id LIMIT 2 OFFSET 10"
+	if gotQ != wantQ {
+		t.Errorf("got query: %s \n             want %s", gotQ, wantQ)
+	}
+}
@@ -5,56 +5,80 @@ import (
 	"strings"
 )
 
-func pqWhere(fields []string, starting int) (query string, next int) {
-	query, next = pqFieldsWithPlaceholder(fields, starting)
-	query = " WHERE " + query
-	return
-}
-
-func pqUpdateSet(fvs []fieldValue, starting int) (query string, args []interface{}, next int) {
-	w := make([]string, len(fvs))
-	for i, fv := range fvs {
-		w[i] = fv.field + " = $" + strconv.Itoa(starting)
-		args = append(args, fv.value)
-		starting++
+//PQ is a Dialect for PostgreSQL database.
+type PQ struct{}
+
+//Placeholder return $n placeholder.
+func (PQ) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+//Quote return the identifier as is, the identifier created by NewTable is already lower case
+//so it doesn't need to be quoted.
+func (PQ) Quote(identifier string) string {
+	return identifier
+}
+
+//LimitOffset return LIMIT n OFFSET m clause.
+func (PQ) LimitOffset(limit, offset int) string {
+	var query string
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
 	}
-	query = strings.Join(w, " AND ")
-	next = starting
-	query = " SET " + query
-	return
+	if offset > 0 {
+		query += " OFFSET " + strconv.Itoa(offset)
+	}
+	return query
 }
 
-func pqFieldsWithPlaceholder(fields []string, starting int) (query string, next int) {
-	w := make([]string, len(fields))
-	for i, field := range fields {
-		w[i] = field + " = $" + strconv.Itoa(starting)
-		starting++
+//Insert return an insert query with RETURNING clause when returning is not empty.
+func (d PQ) Insert(table string, fields []string, rows int, returning []string) string {
+	query := insertQuery(d, table, fields, rows)
+	if len(returning) > 0 {
+		query += " RETURNING " + quoteFields(d, returning)
 	}
-	query = strings.Join(w, " AND ")
-	next = starting
-	return
+	return query
+}
+
+//SupportReturning return true.
+func (PQ) SupportReturning() bool {
+	return true
 }
 
-func pqFilter(filters []filter, starting int) (where string, args []interface{}, next int) {
-	if len(filters) == 0 {
-		return
+//Upsert return INSERT ... ON CONFLICT DO UPDATE query, or DO NOTHING when update is empty.
+func (d PQ) Upsert(table string, fields []string, rows int, conflict, update []string) string {
+	query := insertQuery(d, table, fields, rows) + " ON CONFLICT (" + quoteFields(d, conflict) + ")"
+	if len(update) == 0 {
+		return query + " DO NOTHING"
 	}
-	args = make([]interface{}, len(filters))
-	w := make([]string, len(filters))
-	for i, filter := range filters {
-		w[i] = filter.field + " " + filter.op + " $" + strconv.Itoa(starting)
-		args[i] = filter.value
-		starting++
+	set := make([]string, len(update))
+	for i, field := range update {
+		set[i] = d.Quote(field) + " = EXCLUDED." + d.Quote(field)
 	}
-	next = starting
-	where = " WHERE " + strings.Join(w, " AND ")
-	return where, args, next
+	return query + " DO UPDATE SET " + strings.Join(set, ",")
 }
 
-func pqMakePlaceholder(n int) string {
-	p := make([]string, n)
-	for i := 0; i < n; i++ {
-		p[i] = "$" + strconv.Itoa(i+1)
-	}
-	return strings.Join(p, ",")
+//Like return LIKE or ILIKE query, PostgreSQL use backslash as the default escape character.
+func (PQ) Like(field, op, placeholder string) string {
+	return likeQuery(field, op, placeholder, "", true)
+}
+
+//Lock return FOR UPDATE|SHARE [SKIP LOCKED|NOWAIT] clause.
+func (PQ) Lock(strength, wait string) string {
+	return lockQuery(strength, wait)
+}
+
+//DistinctOn return DISTINCT ON (fields) clause.
+func (PQ) DistinctOn(fields []string) string {
+	return "DISTINCT ON (" + strings.Join(fields, ",") + ") "
+}
+
+//BatchLimit return 65535 args as the number of args of the protocol is 16 bit.
+func (PQ) BatchLimit() (args, rows int) {
+	return 65535, 0
+}
+
+//SupportCopy return true, see Update.Load.
+func (PQ) SupportCopy() bool {
+	return true
 }
@@ -11,6 +11,43 @@ func BenchmarkPqFilter(b *testing.B) {
 		{field: "descr", op: "=", value: "desc"},
 	}
 	for i := 0; i < b.N; i++ {
-		pqFilter(fields, 1)
+		filterQuery(PQ{}, fields, 1)
+	}
+}
+
+func TestPQInsert(t *testing.T) {
+	d := PQ{}
+	var tests = []struct {
+		rows      int
+		returning []string
+		want      string
+	}{
+		{1, nil, "INSERT INTO emp (id,name) VALUES ($1,$2)"},
+		{2, nil, "INSERT INTO emp (id,name) VALUES ($1,$2),($3,$4)"},
+		{1, []string{"id"}, "INSERT INTO emp (id,name) VALUES ($1,$2) RETURNING id"},
+	}
+	for _, test := range tests {
+		got := d.Insert("emp", []string{"id", "name"}, test.rows, test.returning)
+		if got != test.want {
+			t.Errorf("got: %s\n        want %s", got, test.want)
+		}
+	}
+}
+
+func TestPQUpsert(t *testing.T) {
+	d := PQ{}
+	var tests = []struct {
+		update []string
+		want   string
+	}{
+		{nil, "INSERT INTO emp (id,name,age) VALUES ($1,$2,$3) ON CONFLICT (id) DO NOTHING"},
+		{[]string{"name", "age"},
+			"INSERT INTO emp (id,name,age) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name,age = EXCLUDED.age"},
+	}
+	for _, test := range tests {
+		got := d.Upsert("emp", []string{"id", "name", "age"}, 1, []string{"id"}, test.update)
+		if got != test.want {
+			t.Errorf("got: %s\n        want %s", got, test.want)
+		}
 	}
 }
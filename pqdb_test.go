@@ -10,7 +10,7 @@ import (
 	"testing"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 var (
@@ -56,6 +56,40 @@ func TestSelectWithPQDatabase(t *testing.T) {
 	testSetFilterPQ(t, b, want)
 }
 
+func TestLoadPQ(t *testing.T) {
+	if !*pqtest {
+		t.Skip("to run a test for pq database run the test with pqtest,dbuser and dbpasswd flag.")
+	}
+	preparePqTest(t)
+	deleteAllPQData(t)
+	defer deleteAllPQData(t)
+	data := []pqEmp{
+		{"A1", "AN1", 0, newTime(2010, time.January, 1)},
+		{"B2", "BN2", 1, newTime(2010, time.February, 2)},
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin err: %v", err)
+	}
+	n, err := newPqUpdateTest(t).Load(tx, pq.CopyIn, data)
+	if err != nil {
+		tx.Rollback()
+		t.Fatalf("load err: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("commit err: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("got n: %d want 2", n)
+	}
+	got := pqEmp{}
+	empSelect := newPQSelectTest(t)
+	if err = empSelect.GetByPK(db, &got, "B2"); err != nil {
+		t.Errorf("got err: %v want nil", err)
+	}
+	checkResult(t, empSelect, got, data[1])
+}
+
 func testSetFilterPQ(t *testing.T, b *Select, want pqEmp) {
 	query, args := b.Query()
 	got := queryRowPQ(t, query, args...)
@@ -0,0 +1,262 @@
+package qb
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+//Status of the job on the queue.
+const (
+	QueueReady   = "ready"
+	QueueRunning = "running"
+	QueueDone    = "done"
+	QueueFailed  = "failed"
+)
+
+//Beginner is an interface to start a transaction, sql.DB implement Beginner.
+type Beginner interface {
+	Begin() (*sql.Tx, error)
+}
+
+//Queue is a job queue on the table, the job is claimed using FOR UPDATE SKIP LOCKED so
+//the workers doesn't claim the same job. On the database that doesn't support the row locking
+//the job is only claimed when the status and the attempts is not changed since it is selected,
+//so the job claimed by the other worker is skipped.
+//By default the table must have the fields status, priority, attempts and visibleat, see SetColumns.
+//The job is visible when visibleat is passed, the claimed job that is not acknowledged
+//before the visibility timeout is claimed again by the other worker.
+type Queue struct {
+	t           Tabler
+	d           Dialect
+	status      string
+	priority    string
+	attempts    string
+	visibleAt   string
+	maxAttempts int
+	timeout     time.Duration
+	now         func() time.Time
+}
+
+//NewQueue return a queue for the database that use the dialect d,
+//the job is retried 3 times with 5 minutes visibility timeout.
+func NewQueue(d Dialect, t Tabler) *Queue {
+	return &Queue{
+		t:           t,
+		d:           d,
+		status:      "status",
+		priority:    "priority",
+		attempts:    "attempts",
+		visibleAt:   "visibleat",
+		maxAttempts: 3,
+		timeout:     5 * time.Minute,
+		now:         time.Now,
+	}
+}
+
+//NewPQQueue return a queue for PostgreSQL database.
+func NewPQQueue(t Tabler) *Queue {
+	return NewQueue(PQ{}, t)
+}
+
+//NewMySQLQueue return a queue for MySQL 8.0 or later.
+func NewMySQLQueue(t Tabler) *Queue {
+	return NewQueue(MySQL{}, t)
+}
+
+//NewSQLiteQueue return a queue for SQLite database.
+func NewSQLiteQueue(t Tabler) *Queue {
+	return NewQueue(SQLite{}, t)
+}
+
+//SetColumns set the fields of the table that store the status, the priority,
+//the attempts counter and the time the job is visible.
+func (q *Queue) SetColumns(status, priority, attempts, visibleAt string) *Queue {
+	q.status, q.priority, q.attempts, q.visibleAt = status, priority, attempts, visibleAt
+	return q
+}
+
+//SetMaxAttempts set the number of claims before the job is failed.
+func (q *Queue) SetMaxAttempts(n int) *Queue {
+	q.maxAttempts = n
+	return q
+}
+
+//SetTimeout set the visibility timeout of the claimed job.
+func (q *Queue) SetTimeout(d time.Duration) *Queue {
+	q.timeout = d
+	return q
+}
+
+//Claim claim at most n visible jobs ordered by the higher priority first and store it to dst,
+//dst should be pointer to slice of struct. The claimed job is marked as running,
+//the attempts is incremented and it is invisible until the timeout.
+//The running job that is expired on the last attempt is marked as failed.
+func (q *Queue) Claim(db Beginner, dst interface{}, n int) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	now := q.now().UTC()
+	if err = q.failExpired(tx, now); err != nil {
+		return err
+	}
+	s := NewSelect(q.d, q.t, true).
+		SetFilter(q.status, "IN", []string{QueueReady, QueueRunning}).
+		SetFilter(q.visibleAt, "<=", now).
+		SetFilter(q.attempts, "<", q.maxAttempts).
+		OrderBy(Desc(q.priority), q.visibleAt).
+		SetLimit(n)
+	if _, ok := q.d.(locker); ok {
+		s.ForUpdate().SkipLocked()
+	}
+	if err = NewList(s).GetAll(tx, dst); err != nil {
+		return err
+	}
+	jobs := reflect.ValueOf(dst).Elem()
+	claimed := reflect.MakeSlice(jobs.Type(), 0, jobs.Len())
+	for i := 0; i < jobs.Len(); i++ {
+		var ok bool
+		if ok, err = q.claim(tx, jobs.Index(i), now); err != nil {
+			return err
+		}
+		if ok {
+			claimed = reflect.Append(claimed, jobs.Index(i))
+		}
+	}
+	jobs.Set(claimed)
+	return tx.Commit()
+}
+
+//failExpired mark the job that can't be claimed again as failed.
+func (q *Queue) failExpired(dbe DBExecer, now time.Time) error {
+	u := NewUpdate(q.d, q.t)
+	if err := u.Set(q.status, QueueFailed); err != nil {
+		return err
+	}
+	u.SetFilter(q.status, "IN", []string{QueueReady, QueueRunning}).
+		SetFilter(q.visibleAt, "<=", now).
+		SetFilter(q.attempts, ">=", q.maxAttempts)
+	return u.Update(dbe)
+}
+
+//claim mark the job as running and read it again from the database,
+//ok is false when the job is changed by the other worker.
+func (q *Queue) claim(qe QueryDBExecer, job reflect.Value, now time.Time) (ok bool, err error) {
+	var pks []interface{}
+	for _, pk := range q.t.PrimaryKeys() {
+		v := fieldByName(job, pk)
+		if !v.IsValid() {
+			return false, fmt.Errorf("queue: primary key %s doesn't exist on the job", pk)
+		}
+		pks = append(pks, v.Interface())
+	}
+	where, err := q.pkConds(pks)
+	if err != nil {
+		return false, err
+	}
+	for _, field := range []string{q.status, q.attempts} {
+		v := fieldByName(job, field)
+		if !v.IsValid() {
+			return false, fmt.Errorf("queue: field %s doesn't exist on the job", field)
+		}
+		where = append(where, Filter(field, "=", v.Interface()))
+	}
+	u := NewUpdate(q.d, q.t)
+	values := []fieldValue{
+		{q.status, QueueRunning},
+		{q.attempts, Increment(1)},
+		{q.visibleAt, now.Add(q.timeout)},
+	}
+	for _, fv := range values {
+		if err = u.Set(fv.field, fv.value); err != nil {
+			return false, err
+		}
+	}
+	query, args := u.Where(where...).UpdateQuery()
+	res, err := qe.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	return true, NewSelect(q.d, q.t, true).GetByPK(qe, job.Addr().Interface(), pks...)
+}
+
+//Ack mark the job with the primary keys as done.
+func (q *Queue) Ack(dbe DBExecer, pk ...interface{}) error {
+	return q.setStatus(dbe, QueueDone, pk)
+}
+
+//Fail mark the job with the primary keys as failed so it is not claimed again.
+func (q *Queue) Fail(dbe DBExecer, pk ...interface{}) error {
+	return q.setStatus(dbe, QueueFailed, pk)
+}
+
+//Retry make the job with the primary keys visible again after the delay,
+//the job that reach the max attempts is marked as failed instead.
+func (q *Queue) Retry(dbe DBExecer, delay time.Duration, pk ...interface{}) error {
+	where, err := q.pkConds(pk)
+	if err != nil {
+		return err
+	}
+	values := []fieldValue{
+		{q.status, QueueReady},
+		{q.visibleAt, q.now().UTC().Add(delay)},
+	}
+	if err = q.update(dbe, values, append(where, Filter(q.attempts, "<", q.maxAttempts))...); err != nil {
+		return err
+	}
+	values = []fieldValue{{q.status, QueueFailed}}
+	return q.update(dbe, values, append(where, Filter(q.attempts, ">=", q.maxAttempts))...)
+}
+
+//update set the values of the jobs that match the conds on a single query.
+func (q *Queue) update(dbe DBExecer, values []fieldValue, conds ...Condition) error {
+	sets := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, fv := range values {
+		sets[i] = q.d.Quote(fv.field) + " = " + q.d.Placeholder(i+1)
+		args[i] = fv.value
+	}
+	filters := make([]filter, len(conds))
+	for i, cond := range conds {
+		filters[i] = cond.asFilter()
+	}
+	where, argsW, _ := filterQuery(q.d, filters, len(values)+1)
+	query := "UPDATE " + q.d.Quote(q.t.TableName()) + " SET " + strings.Join(sets, ",") + where
+	_, err := dbe.Exec(query, append(args, argsW...)...)
+	return err
+}
+
+func (q *Queue) setStatus(dbe DBExecer, status string, pk []interface{}) error {
+	if len(pk) != len(q.t.PrimaryKeys()) {
+		return errors.New("queue: len of args mismatch with len of Primary Keys")
+	}
+	u := NewUpdate(q.d, q.t)
+	if err := u.Set(q.status, status); err != nil {
+		return err
+	}
+	return u.UpdateByPK(dbe, pk...)
+}
+
+func (q *Queue) pkConds(pk []interface{}) ([]Condition, error) {
+	pks := q.t.PrimaryKeys()
+	if len(pk) != len(pks) {
+		return nil, errors.New("queue: len of args mismatch with len of Primary Keys")
+	}
+	conds := make([]Condition, len(pks))
+	for i, field := range pks {
+		conds[i] = Filter(field, "=", pk[i])
+	}
+	return conds, nil
+}
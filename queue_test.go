@@ -0,0 +1,173 @@
+package qb
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type queueJob struct {
+	ID        string `pk:"1"`
+	Priority  int
+	Status    string
+	Attempts  int
+	VisibleAt time.Time
+}
+
+func openQueueTest(t *testing.T, now time.Time) (*sql.DB, *Queue) {
+	columns := "ID varchar PRIMARY KEY,Priority int,Status varchar,Attempts int,VisibleAt timestamp"
+	sdb, tbl := openSQLiteTable(t, "job", columns, queueJob{})
+	u := NewSQLiteUpdate(tbl)
+	jobs := []queueJob{
+		{"a", 1, QueueReady, 0, now.Add(-time.Minute)},
+		{"b", 5, QueueReady, 0, now.Add(-time.Minute)},
+		{"c", 5, QueueReady, 0, now.Add(time.Minute)},
+		{"d", 3, QueueDone, 1, now.Add(-time.Minute)},
+		{"e", 3, QueueRunning, 1, now.Add(-time.Second)},
+	}
+	for _, job := range jobs {
+		if err := u.Insert(sdb, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	queue := NewSQLiteQueue(tbl).SetMaxAttempts(2).SetTimeout(time.Hour)
+	queue.now = func() time.Time { return now }
+	return sdb, queue
+}
+
+func claimIDs(t *testing.T, sdb *sql.DB, q *Queue, n int) []queueJob {
+	var jobs []queueJob
+	if err := q.Claim(sdb, &jobs, n); err != nil {
+		t.Fatal(err)
+	}
+	return jobs
+}
+
+func TestQueueClaim(t *testing.T) {
+	now := newTime(2020, time.January, 1)
+	sdb, q := openQueueTest(t, now)
+	jobs := claimIDs(t, sdb, q, 2)
+	if len(jobs) != 2 || jobs[0].ID != "b" || jobs[1].ID != "e" {
+		t.Fatalf("got jobs: %v want b and e", jobs)
+	}
+	if jobs[1].Status != QueueRunning || jobs[1].Attempts != 2 || !jobs[1].VisibleAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("got job: %v want running with 2 attempts", jobs[1])
+	}
+	jobs = claimIDs(t, sdb, q, 5)
+	if len(jobs) != 1 || jobs[0].ID != "a" {
+		t.Fatalf("got jobs: %v want a", jobs)
+	}
+	if jobs = claimIDs(t, sdb, q, 5); len(jobs) != 0 {
+		t.Fatalf("got jobs: %v want empty", jobs)
+	}
+
+	//the job that is not acknowledged is visible after the timeout.
+	q.now = func() time.Time { return now.Add(2 * time.Hour) }
+	jobs = claimIDs(t, sdb, q, 5)
+	if len(jobs) != 3 || jobs[0].ID != "c" || jobs[1].ID != "b" || jobs[2].ID != "a" {
+		t.Fatalf("got jobs: %v want c, b and a", jobs)
+	}
+}
+
+func TestQueueAckRetry(t *testing.T) {
+	now := newTime(2020, time.January, 1)
+	sdb, q := openQueueTest(t, now)
+	jobs := claimIDs(t, sdb, q, 3)
+	if len(jobs) != 3 {
+		t.Fatalf("got jobs: %v want 3 jobs", jobs)
+	}
+	if err := q.Ack(sdb, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Retry(sdb, time.Minute, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Retry(sdb, time.Minute, "e"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Fail(sdb, "a", "x"); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+
+	s := NewSQLiteSelect(q.t, false).SetFields("id", "status", "attempts").OrderBy("id")
+	var got []queueJob
+	if err := NewList(s).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"a": QueueReady, "b": QueueDone, "c": QueueReady, "d": QueueDone, "e": QueueFailed}
+	for _, job := range got {
+		if job.Status != want[job.ID] {
+			t.Errorf("got job %s status: %s want %s", job.ID, job.Status, want[job.ID])
+		}
+	}
+
+	q.now = func() time.Time { return now.Add(2 * time.Minute) }
+	jobs = claimIDs(t, sdb, q, 5)
+	if len(jobs) != 2 || jobs[0].ID != "c" || jobs[1].ID != "a" || jobs[1].Attempts != 2 {
+		t.Fatalf("got jobs: %v want c and a", jobs)
+	}
+}
+
+func TestQueueFailExpired(t *testing.T) {
+	now := newTime(2020, time.January, 1)
+	sdb, q := openQueueTest(t, now)
+	q.SetMaxAttempts(1)
+	jobs := claimIDs(t, sdb, q, 1)
+	if len(jobs) != 1 || jobs[0].ID != "b" {
+		t.Fatalf("got jobs: %v want b", jobs)
+	}
+	//b is expired on the last attempt.
+	q.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if jobs = claimIDs(t, sdb, q, 5); len(jobs) != 2 || jobs[0].ID != "c" || jobs[1].ID != "a" {
+		t.Fatalf("got jobs: %v want c and a", jobs)
+	}
+	var status string
+	if err := sdb.QueryRow("SELECT status FROM job WHERE id = 'b'").Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status != QueueFailed {
+		t.Errorf("got status: %s want %s", status, QueueFailed)
+	}
+}
+
+func TestQueueClaimChanged(t *testing.T) {
+	now := newTime(2020, time.January, 1)
+	sdb, q := openQueueTest(t, now)
+	stale := queueJob{ID: "a", Status: QueueReady}
+	claimIDs(t, sdb, q, 5)
+	//a is claimed by the other worker after it is selected.
+	ok, err := q.claim(sdb, reflect.ValueOf(&stale).Elem(), now)
+	if err != nil || ok {
+		t.Errorf("got ok: %v err: %v want false and nil", ok, err)
+	}
+}
+
+type queueNullJob struct {
+	ID        string `pk:"1"`
+	Priority  int
+	Status    sql.NullString
+	Attempts  sql.NullInt64
+	VisibleAt sql.NullTime
+}
+
+func TestQueueClaimNullTypes(t *testing.T) {
+	now := newTime(2020, time.January, 1)
+	sdb, q := openQueueTest(t, now)
+	tbl, err := NewTable("job", queueNullJob{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.t = tbl
+	var jobs []queueNullJob
+	if err := q.Claim(sdb, &jobs, 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "b" {
+		t.Fatalf("got jobs: %v want b", jobs)
+	}
+	job := jobs[0]
+	if job.Status.String != QueueRunning || job.Attempts.Int64 != 1 || !job.VisibleAt.Time.Equal(now.Add(time.Hour)) {
+		t.Errorf("got job: %+v want running with 1 attempt", job)
+	}
+}
@@ -1,16 +1,27 @@
 //Package qb is simple library to construct SQL Query.
 //Supported filter of are :
 //	"="  equal
+//	"<>" not equal
 //	">"  greater than
 //	"<"  less than
 //	">=" greater than and equal
 //	"<=" less than and equal
+//	"IN" in the slice value
+//	"NOT IN" not in the slice value
+//	"IS NULL" is null, the value is ignored
+//	"IS NOT NULL" is not null, the value is ignored
+//	"LIKE" match the pattern, see EscapeLike
+//	"NOT LIKE" doesn't match the pattern
+//	"ILIKE" match the pattern case insensitive
+//	"NOT ILIKE" doesn't match the pattern case insensitive
+//Filter with nil value using "=" or "<>" is translated to "IS NULL" or "IS NOT NULL".
+//The filter value can be a *Select to compare the field with the subquery, e.g. Filter("id", "IN", s),
+//see also Exists and SubQuery.
 package qb
 
 import (
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -22,42 +33,90 @@ type Tabler interface {
 	PrimaryKeys() []string
 }
 
-type filter struct {
-	field string
-	op    string
-	value interface{}
-}
-
 //Select is builder to construct Select Query.
 type Select struct {
-	explicit bool
-	t        Tabler
-	driver   string
-	fields   []string
-	orderBy  []string
-	filters  []filter
-	limit    int
-	offset   int
+	explicit   bool
+	t          Tabler
+	d          Dialect
+	fields     []string
+	orderBy    []string
+	filters    []filter
+	limit      int
+	offset     int
+	alias      string
+	joins      []join
+	groupBy    []string
+	having     []filter
+	ctes       []cte
+	compound   []compound
+	lock       string
+	wait       string
+	distinct   bool
+	distinctOn []string
+	exprs      map[string]Expr
+	//outer is the query that use the select as the subquery while checking the error.
+	outer *Select
+}
+
+//NewSelect create a builder for the database that use the dialect d.
+//If explicit, when fields is not specified the builder will return query with the fields,
+//instead of *.
+func NewSelect(d Dialect, t Tabler, explicit bool) *Select {
+	return &Select{explicit: explicit, t: t, d: d}
 }
 
 //NewPQSelect create a builder for PostgreSQL database.
 //If explicit, when fields is not specified the builder will return query with the fields,
 //instead of *.
 func NewPQSelect(t Tabler, explicit bool) *Select {
-	return &Select{explicit: explicit, t: t, driver: "pq"}
+	return NewSelect(PQ{}, t, explicit)
+}
+
+//NewMySQLSelect create a builder for MySQL and MariaDB database.
+//If explicit, when fields is not specified the builder will return query with the fields,
+//instead of *.
+func NewMySQLSelect(t Tabler, explicit bool) *Select {
+	return NewSelect(MySQL{}, t, explicit)
+}
+
+//NewSQLiteSelect create a builder for SQLite database.
+//If explicit, when fields is not specified the builder will return query with the fields,
+//instead of *.
+func NewSQLiteSelect(t Tabler, explicit bool) *Select {
+	return NewSelect(SQLite{}, t, explicit)
+}
+
+//NewSQLServerSelect create a builder for Microsoft SQL Server database.
+//If explicit, when fields is not specified the builder will return query with the fields,
+//instead of *.
+func NewSQLServerSelect(t Tabler, explicit bool) *Select {
+	return NewSelect(SQLServer{}, t, explicit)
 }
 
 //GetByPK execute the query using qe with aargs and save the result to dst.
 //dst must be pointer to struct.
+//When the select has a lock, qe should be a transaction to lock the row until the end of the transaction.
 func (s *Select) GetByPK(qe QueryExecer, dst interface{}, args ...interface{}) error {
 	if len(args) == 0 {
 		return errors.New("args is invalid")
 	}
-	row := qe.QueryRow(s.SelectByPK(), args...)
-	err := scanWithReflection(s.t.Fields(), row, dst)
+	if err := s.lockError(); err != nil {
+		return err
+	}
+	query, joinArgs := s.selectByPK()
+	row := qe.QueryRow(query, append(joinArgs, args...)...)
+	err := scanWithReflection(s.scanFields(), row, dst)
 	return err
 }
 
+//scanFields return the fields to scan the result of the query.
+func (s *Select) scanFields() []string {
+	if len(s.fields) != 0 {
+		return s.fields
+	}
+	return s.t.Fields()
+}
+
 //GetByPKWithCursor execute the query using qe with aargs and save the result to dst.
 //dst must be pointer to struct.
 func (s *Select) GetByPKWithCursor(qe QueryExecer, dst interface{}, args ...interface{}) (cursor string, err error) {
@@ -71,13 +130,15 @@ func (s *Select) GetByPKWithCursor(qe QueryExecer, dst interface{}, args ...inte
 
 func (s *Select) byPKCursor(qe QueryExecer, args ...interface{}) (cursor string, err error) {
 	c := Cursor{limit: 1, fields: s.fields, orderBy: s.orderBy}
-	orderBy := s.orderByQuery()
-	where, _ := s.pkWhereQuery(1)
+	with, withArgs, next := s.withQuery(1)
+	orderBy, orderArgs, next := s.windowOrderQuery(next)
+	from, joinArgs, next := s.fromQuery(next)
+	where, _ := whereQuery(s.d, s.t.PrimaryKeys(), next)
 
-	q := "SELECT row_number FROM (SELECT " + strings.Join(s.t.PrimaryKeys(), ",") +
-		",row_number() OVER (" + orderBy + " ) FROM " + s.t.TableName() + ") as xxrn" + where
+	q := with + "SELECT rn FROM (SELECT " + quoteFields(s.d, s.qualify(s.t.PrimaryKeys())) +
+		",row_number() OVER (" + orderBy + " ) AS rn" + from + ") as xxrn" + where
 	// fmt.Println("query:", q)
-	err = qe.QueryRow(q, args...).Scan(&c.offset)
+	err = qe.QueryRow(q, append(append(append(withArgs, orderArgs...), joinArgs...), args...)...).Scan(&c.offset)
 	if err != nil {
 		return cursor, err
 	}
@@ -102,25 +163,31 @@ func (s *Select) Get(qe QueryExecer) (*List, error) {
 
 //GetNext get the next row based on the cursor save it to dst.
 func (s *Select) GetNext(qe QueryExecer, dst interface{}, cursor string) error {
+	if err := s.lockError(); err != nil {
+		return err
+	}
 	if err := s.setCursor(cursor); err != nil {
 		return err
 	}
 	s.offset += s.limit
 	query, args := s.Query()
 	row := qe.QueryRow(query, args...)
-	err := scanWithReflection(s.t.Fields(), row, dst)
+	err := scanWithReflection(s.scanFields(), row, dst)
 	return err
 }
 
 //GetNext get the next row based on the cursor save it to dst.
 func (s *Select) GetPrevious(qe QueryExecer, dst interface{}, cursor string) error {
+	if err := s.lockError(); err != nil {
+		return err
+	}
 	if err := s.setCursor(cursor); err != nil {
 		return err
 	}
 	s.offset -= s.limit
 	query, args := s.Query()
 	row := qe.QueryRow(query, args...)
-	err := scanWithReflection(s.t.Fields(), row, dst)
+	err := scanWithReflection(s.scanFields(), row, dst)
 	return err
 }
 
@@ -128,13 +195,16 @@ func (s *Select) GetLast(qe QueryExecer, dst interface{}, cursor string) error {
 	if err := s.getLast(qe, cursor); err != nil {
 		return err
 	}
-	query, args := s.Query()
+	query, args := s.lastQuery()
 	row := qe.QueryRow(query, args...)
-	err := scanWithReflection(s.t.Fields(), row, dst)
+	err := scanWithReflection(s.scanFields(), row, dst)
 	return err
 }
 
 func (s *Select) getLast(qe QueryExecer, cursor string) error {
+	if err := s.lockError(); err != nil {
+		return err
+	}
 	if err := s.setCursor(cursor); err != nil {
 		return err
 	}
@@ -149,9 +219,65 @@ func (s *Select) getLast(qe QueryExecer, cursor string) error {
 	return nil
 }
 
+//lastQuery return the query to get the last rows, the rows is taken from the start of
+//the inverted order so the database doesn't need to skip the rows before the offset,
+//and then sorted back to the order of the query.
+func (s *Select) lastQuery() (query string, args []interface{}) {
+	if s.limit <= 0 || !s.isOrderBySelected() {
+		return s.Query()
+	}
+	top, limit := s.limitQuery(s.limit, 0)
+	with, args, next := s.withQuery(1)
+	query, bodyArgs, _ := s.bodyQuery(top, next)
+	query += s.orderQuery(true) + limit + s.lockQuery()
+	query = with + "SELECT * FROM (" + query + ") AS xxlast" + s.orderQuery(false)
+	return query, append(args, bodyArgs...)
+}
+
+//isOrderBySelected report whether all of the orderBy fields is selected by the query,
+//so the rows can be sorted again outside of the query.
+//The order of DISTINCT ON can't be inverted as it choose the row that is kept.
+func (s *Select) isOrderBySelected() bool {
+	s.orderQuery(false)
+	if len(s.joins) != 0 || len(s.distinctOn) != 0 {
+		return false
+	}
+	if len(s.fields) == 0 {
+		return true
+	}
+	for _, v := range s.orderBy {
+		o, _ := parseOrder(v)
+		found := false
+		for _, field := range s.fields {
+			if scanName(field) == o.field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Select) getCount(qe QueryExecer) (int, error) {
-	where, args, _ := s.filterQuery(1)
-	query := "SELECT count(*) FROM " + s.t.TableName() + where
+	with, args, next := s.withQuery(1)
+	if len(s.compound) != 0 || s.distinct || len(s.distinctOn) != 0 {
+		var count int
+		query, bodyArgs, _ := s.bodyQuery("", next)
+		query = with + "SELECT count(*) FROM (" + query + ") AS xxcount"
+		err := qe.QueryRow(query, append(args, bodyArgs...)...).Scan(&count)
+		return count, err
+	}
+	from, fromArgs, next := s.fromQuery(next)
+	where, whereArgs, next := s.filterQuery(next)
+	group, groupArgs, _ := s.groupQuery(next)
+	args = append(append(append(args, fromArgs...), whereArgs...), groupArgs...)
+	query := with + "SELECT count(*)" + from + where
+	if group != "" {
+		query = with + "SELECT count(*) FROM (SELECT 1 AS xx" + from + where + group + ") AS xxcount"
+	}
 	var count int
 	err := qe.QueryRow(query, args...).Scan(&count)
 	return count, err
@@ -167,6 +293,12 @@ func (s *Select) setCursor(cursor string) error {
 		s.fields = c.fields
 	}
 	if len(c.filters) != 0 {
+		//the filter with subquery or expression is not stored on the cursor so it is kept from the builder.
+		for _, f := range s.filters {
+			if f.hasQueryValue() {
+				c.filters = append(c.filters, f)
+			}
+		}
 		s.filters = c.filters
 	}
 	if len(c.orderBy) != 0 {
@@ -182,9 +314,15 @@ func (s *Select) Cursor() string {
 		s.limit <= 0 && s.offset == 0 {
 		return ""
 	}
+	var filters []filter
+	for _, f := range s.filters {
+		if !f.hasQueryValue() {
+			filters = append(filters, f)
+		}
+	}
 	c := Cursor{
 		fields:  s.fields,
-		filters: s.filters,
+		filters: filters,
 		orderBy: s.orderBy,
 		limit:   s.limit,
 		offset:  s.offset,
@@ -202,12 +340,17 @@ func (s *Select) SetFields(fieldName ...string) *Select {
 
 //SetFilter set the where clause for the query, filter will be AND with other filter.
 func (s *Select) SetFilter(fieldName string, op string, value interface{}) *Select {
-	f := filter{
-		field: strings.ToLower(fieldName),
-		op:    op,
-		value: value,
+	s.filters = append(s.filters, newFilter(fieldName, op, value))
+	return s
+}
+
+//Where set the conditions for the where clause, conditions will be AND with other filter.
+//Use Or to get the rows that match any of the conditions:
+//	Where(Or(Filter("status", "=", "a"), Filter("status", "=", "b")))
+func (s *Select) Where(conds ...Condition) *Select {
+	for _, cond := range conds {
+		s.filters = append(s.filters, asFilter(cond))
 	}
-	s.filters = append(s.filters, f)
 	return s
 }
 
@@ -223,6 +366,9 @@ func (s *Select) SetRange(fieldName string, starting, ending interface{}) *Selec
 
 //OrderBy set the order by for the query.
 //If OrderBy is not called the query will orderBy primaryKey fields.
+//The order of the field can be specified as "field [ASC|DESC] [NULLS FIRST|NULLS LAST]"
+//or using Asc, Desc, NullsFirst and NullsLast:
+//	OrderBy(NullsLast(Desc("joindate")), Asc("id"))
 func (s *Select) OrderBy(fieldName ...string) *Select {
 	s.orderBy = fieldName
 	return s
@@ -243,45 +389,136 @@ func (s *Select) SetOffset(n int) *Select {
 
 //SelectAll return a query to select all data from sql.
 func (s *Select) SelectAll() string {
-	query := s.initialQuery() + s.orderByQuery()
+	with, _, next := s.withQuery(1)
+	query, _, _ := s.initialQuery("", next)
+	query = with + query + s.orderByQuery()
 	return query
 }
 
 //SelectByPK return a query with the where clause from PrimaryKey.
+//The args of the query is the args of the join conditions followed by the primary keys.
 func (s *Select) SelectByPK() string {
-	where, _ := s.pkWhereQuery(1)
-	query := s.initialQuery() + where
+	query, _ := s.selectByPK()
 	return query
 }
 
-func (s *Select) initialQuery() string {
+func (s *Select) selectByPK() (query string, args []interface{}) {
+	with, args, next := s.withQuery(1)
+	query, selectArgs, next := s.initialQuery("", next)
+	where, _ := s.pkWhereQuery(next)
+	return with + query + where + s.lockQuery(), append(args, selectArgs...)
+}
+
+//initialQuery return SELECT ... FROM query, top is rendered after SELECT.
+func (s *Select) initialQuery(top string, starting int) (query string, args []interface{}, next int) {
 	if len(s.fields) == 0 {
 		if !s.explicit {
-			return "SELECT * FROM " + s.t.TableName()
+			from, args, next := s.fromQuery(starting)
+			return "SELECT " + s.distinctQuery() + top + "*" + from, args, next
+		}
+		s.fields = s.tableFields()
+	}
+	fields := make([]string, len(s.fields))
+	next = starting
+	for i, field := range s.fields {
+		name, alias := splitAlias(field)
+		if e, ok := s.exprs[name]; ok {
+			var exprArgs []interface{}
+			fields[i], exprArgs, next = e.build(s.d, next)
+			args = append(args, exprArgs...)
+			if alias == "" {
+				alias = name
+			}
+		} else {
+			fields[i] = quoteField(s.d, name)
+		}
+		if alias != "" {
+			fields[i] += " AS " + s.d.Quote(alias)
 		}
-		s.fields = s.t.Fields()
 	}
-	query := "SELECT " + strings.Join(s.fields, ",") + " FROM " + s.t.TableName()
-	return query
+	//the args of the fields is before the args of the joins.
+	from, fromArgs, next := s.fromQuery(next)
+	query = "SELECT " + s.distinctQuery() + top + strings.Join(fields, ",") + from
+	return query, append(args, fromArgs...), next
 }
 
 func (s *Select) orderByQuery() string {
-	if len(s.orderBy) == 0 {
-		s.orderBy = append(s.orderBy, s.t.PrimaryKeys()...)
+	return s.orderQuery(false)
+}
+
+//orderQuery return the ORDER BY clause, when invert the rows is sorted in reverse.
+func (s *Select) orderQuery(invert bool) string {
+	if len(s.groupBy) != 0 {
+		//the primary keys can't be used to order the groups.
+		if len(s.orderBy) == 0 {
+			s.orderBy = append(s.orderBy, s.groupBy...)
+		}
 	} else {
-		for _, v := range s.t.PrimaryKeys() {
-			if !s.isOrderByExist(v) {
-				s.orderBy = append(s.orderBy, v)
+		if len(s.orderBy) == 0 {
+			s.orderBy = append(s.orderBy, s.distinctOn...)
+		}
+		if len(s.orderBy) == 0 && s.distinct {
+			for _, field := range s.fields {
+				s.orderBy = append(s.orderBy, scanName(field))
+			}
+		}
+		for _, v := range s.qualify(s.t.PrimaryKeys()) {
+			if s.isOrderByExist(v) || (s.distinct && !s.isSelected(v)) {
+				continue
 			}
+			s.orderBy = append(s.orderBy, v)
 		}
 	}
-	orderBy := " ORDER BY " + strings.Join(s.orderBy, ",")
+	orders := make([]string, len(s.orderBy))
+	for i, v := range s.orderBy {
+		o, _ := parseOrder(v)
+		if invert {
+			o = o.invert()
+		}
+		orders[i] = orderItem(s.d, s.orderField(o.field), o.desc, o.nulls)
+	}
+	if len(orders) == 0 {
+		//the subquery table may not have the primary keys to order the rows.
+		return ""
+	}
+	orderBy := " ORDER BY " + strings.Join(orders, ",")
 	return orderBy
 }
 
+//orderField return the quoted field to order the rows, the expression is referred by the alias
+//when it is selected.
+func (s *Select) orderField(field string) string {
+	e, ok := s.exprs[field]
+	if !ok || s.isExprSelected(field) {
+		return quoteField(s.d, field)
+	}
+	query, _, _ := e.build(s.d, 1)
+	return query
+}
+
+//windowOrderQuery return the ORDER BY clause of the window function on the query that only select
+//the primary keys, so the expression is rendered instead of referred by the alias.
+func (s *Select) windowOrderQuery(starting int) (query string, args []interface{}, next int) {
+	if s.orderByQuery() == "" {
+		return "", nil, starting
+	}
+	orders := make([]string, len(s.orderBy))
+	for i, v := range s.orderBy {
+		o, _ := parseOrder(v)
+		field := quoteField(s.d, o.field)
+		if e, ok := s.exprs[o.field]; ok {
+			var exprArgs []interface{}
+			field, exprArgs, starting = e.build(s.d, starting)
+			args = append(args, exprArgs...)
+		}
+		orders[i] = orderItem(s.d, field, o.desc, o.nulls)
+	}
+	return " ORDER BY " + strings.Join(orders, ","), args, starting
+}
+
 func (s *Select) isOrderByExist(field string) bool {
 	for _, v := range s.orderBy {
-		if field == v {
+		if o, _ := parseOrder(v); field == o.field {
 			return true
 		}
 	}
@@ -289,17 +526,11 @@ func (s *Select) isOrderByExist(field string) bool {
 }
 
 func (s *Select) pkWhereQuery(starting int) (string, int) {
-	if s.driver == "pq" {
-		return pqWhere(s.t.PrimaryKeys(), starting)
-	}
-	return "pkWhereQuery: unreacheable", 0
+	return whereQuery(s.d, s.qualify(s.t.PrimaryKeys()), starting)
 }
 
 func (s *Select) filterQuery(starting int) (where string, args []interface{}, next int) {
-	if s.driver == "pq" {
-		return pqFilter(s.filters, starting)
-	}
-	return
+	return filterQuery(s.d, s.exprFilters(s.filters), starting)
 }
 
 //Error check the query
@@ -313,20 +544,86 @@ func (s *Select) Error() error {
 	if err := s.filterError(); err != nil {
 		return err
 	}
+	if err := s.joinError(); err != nil {
+		return err
+	}
+	if err := s.groupError(); err != nil {
+		return err
+	}
+	if err := s.cteError(); err != nil {
+		return err
+	}
+	if err := s.compoundError(); err != nil {
+		return err
+	}
+	if err := s.lockError(); err != nil {
+		return err
+	}
+	if err := s.distinctError(); err != nil {
+		return err
+	}
+	if err := s.exprError(); err != nil {
+		return err
+	}
 	return nil
 }
 
 //Query return a query without checking the error.
 func (s *Select) Query() (query string, args []interface{}) {
-	where, args, _ := s.filterQuery(1)
-	query = s.initialQuery() + where + s.orderByQuery()
-	if s.limit > 0 {
-		query += " LIMIT " + strconv.Itoa(s.limit)
+	query, args, _ = s.buildQuery(1)
+	return query, args
+}
+
+//buildQuery return the query with the placeholder number starting from starting,
+//it is used to build the subquery after the placeholder of the outer query.
+func (s *Select) buildQuery(starting int) (query string, args []interface{}, next int) {
+	top, limit := s.limitQuery(s.limit, s.offset)
+	with, args, next := s.withQuery(starting)
+	query, bodyArgs, next := s.bodyQuery(top, next)
+	query = with + query + s.orderByQuery() + limit + s.lockQuery()
+	return query, append(args, bodyArgs...), next
+}
+
+//nestedQuery return the query to use as the subquery, the derived table or the common table expression.
+//ORDER BY is only rendered when the rows is limited or it is needed by DISTINCT ON,
+//SQL Server doesn't allow ORDER BY on the nested query without TOP or OFFSET.
+func (s *Select) nestedQuery(starting int) (query string, args []interface{}, next int) {
+	if s.limit > 0 || s.offset > 0 || len(s.distinctOn) != 0 {
+		return s.buildQuery(starting)
 	}
-	if s.offset > 0 {
-		query += " OFFSET " + strconv.Itoa(s.offset)
+	with, args, next := s.withQuery(starting)
+	query, bodyArgs, next := s.bodyQuery("", next)
+	query = with + query + s.lockQuery()
+	return query, append(args, bodyArgs...), next
+}
+
+//bodyQuery return the query without WITH, ORDER BY and LIMIT clause, the compound query is included.
+func (s *Select) bodyQuery(top string, starting int) (query string, args []interface{}, next int) {
+	query, args, next = s.initialQuery(top, starting)
+	where, whereArgs, next := s.filterQuery(next)
+	group, groupArgs, next := s.groupQuery(next)
+	args = append(append(args, whereArgs...), groupArgs...)
+	query += where + group
+	for _, c := range s.compound {
+		part, partArgs, partNext := c.s.bodyQuery("", next)
+		query += " " + c.op + " " + part
+		args, next = append(args, partArgs...), partNext
 	}
-	return query, args
+	return query, args, next
+}
+
+//limitQuery return the clause after SELECT and the clause after ORDER BY that limit the rows.
+func (s *Select) limitQuery(limit, offset int) (top, query string) {
+	t, ok := s.d.(topper)
+	if !ok {
+		return "", s.d.LimitOffset(limit, offset)
+	}
+	top = t.Top(limit, offset)
+	if top != "" && len(s.compound) != 0 {
+		//TOP would limit only the first select of the compound query.
+		return "", t.Fetch(limit)
+	}
+	return top, s.d.LimitOffset(limit, offset)
 }
 
 //Reset zeroes the fields,filters and orderBy so the builder can be reuse to construct new query
@@ -337,29 +634,47 @@ func (s *Select) Reset() *Select {
 	s.orderBy = []string{}
 	s.limit = 0
 	s.offset = 0
+	s.groupBy = []string{}
+	s.having = []filter{}
+	s.compound = []compound{}
+	s.lock = ""
+	s.wait = ""
+	s.distinct = false
+	s.distinctOn = []string{}
 	return s
 }
 
-func (s *Select) makePlaceholder(n int) string {
-	if s.driver == "pq" {
-		return pqMakePlaceholder(n)
+//isAlias report whether name is the alias of the selected field.
+func (s *Select) isAlias(name string) bool {
+	for _, field := range s.fields {
+		if _, alias := splitAlias(field); alias != "" && alias == name {
+			return true
+		}
 	}
-	return "makePlaceholder: should not happen"
+	return false
 }
 
 func (s *Select) fieldError() error {
+	if err := derivedError(s.t); err != nil {
+		return err
+	}
 	for _, field := range s.fields {
-		if !s.fieldExist(field) {
-			return fmt.Errorf("field %s doesn't exist", field)
+		name, _ := splitAlias(field)
+		if !s.fieldExist(name) {
+			return fmt.Errorf("field %s doesn't exist", name)
 		}
 	}
 	return nil
 }
 
 func (s *Select) orderByError() error {
-	for _, field := range s.orderBy {
-		if !s.fieldExist(field) {
-			return fmt.Errorf("orderBy field %s doesn't exist", field)
+	for _, v := range s.orderBy {
+		o, err := parseOrder(v)
+		if err != nil {
+			return err
+		}
+		if !s.fieldExist(o.field) && !s.isAlias(o.field) {
+			return fmt.Errorf("orderBy field %s doesn't exist", o.field)
 		}
 	}
 	return nil
@@ -367,19 +682,86 @@ func (s *Select) orderByError() error {
 
 func (s *Select) filterError() error {
 	for _, filter := range s.filters {
-		if !s.fieldExist(filter.field) {
-			return fmt.Errorf("field %s doesn't exist", filter.field)
-		}
-		if err := s.isValidOp(filter.op); err != nil {
+		if err := s.checkFilter(filter); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (s *Select) checkFilter(f filter) error {
+	if err := f.opError(); err != nil {
+		return err
+	}
+	if f.isGroup() {
+		for _, cond := range f.conds {
+			if err := s.checkFilter(cond); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if sub, ok := f.value.(*Select); ok {
+		sub.outer = s
+		err := sub.Error()
+		sub.outer = nil
+		if err != nil {
+			return fmt.Errorf("subquery: %v", err)
+		}
+		if isExistsOp(f.op) {
+			return nil
+		}
+	} else if isExistsOp(f.op) {
+		return fmt.Errorf("filter op %s need a subquery", f.op)
+	}
+	if !s.fieldExist(f.field) {
+		return fmt.Errorf("field %s doesn't exist", f.field)
+	}
+	if col, ok := f.value.(Col); ok && !s.colExist(string(col)) {
+		return fmt.Errorf("field %s doesn't exist", col)
+	}
+	if err := s.isValidOp(f.op); err != nil {
+		return err
+	}
+	if _, ok := listValues(f.value); isListOp(f.op) && !ok && !f.hasQueryValue() {
+		return fmt.Errorf("filter op %s on field %s need a slice value", f.op, f.field)
+	}
+	return nil
+}
+
+//fieldExist report whether the field exist on the table, the field qualified with
+//the table name or alias is checked against the fields of the qualified table.
 func (s *Select) fieldExist(field string) bool {
-	for _, val := range s.t.Fields() {
-		if val == field {
+	if _, ok := s.exprs[field]; ok {
+		return true
+	}
+	if fn, arg, ok := splitAggregate(field); ok {
+		if arg == "*" {
+			return fn == "count"
+		}
+		field = arg
+	}
+	qualifier, name := splitQualifier(strings.ToLower(field))
+	if qualifier != "" {
+		t, err := s.tablerOf(qualifier)
+		return err == nil && isFieldExist(t, name)
+	}
+	if isFieldExist(s.t, name) {
+		return true
+	}
+	for _, j := range s.joins {
+		if isFieldExist(j.t, name) {
+			return true
+		}
+	}
+	return false
+}
+
+//colExist report whether the field exist on the select or on the outer query,
+//so the subquery can refer the fields of the outer query.
+func (s *Select) colExist(field string) bool {
+	for ; s != nil; s = s.outer {
+		if s.fieldExist(field) {
 			return true
 		}
 	}
@@ -387,7 +769,8 @@ func (s *Select) fieldExist(field string) bool {
 }
 
 func (s *Select) isValidOp(op string) error {
-	if op == "=" || op == "<" || op == ">" || op == ">=" || op == "<=" {
+	if op == "=" || op == "<>" || op == "!=" || op == "<" || op == ">" || op == ">=" || op == "<=" ||
+		isListOp(op) || isNullOp(op) || isLikeOp(op) {
 		return nil
 	}
 	return fmt.Errorf("filter op %s is not supported", op)
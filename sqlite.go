@@ -0,0 +1,63 @@
+package qb
+
+import (
+	"strconv"
+	"strings"
+)
+
+//SQLite is a Dialect for SQLite database.
+type SQLite struct{}
+
+//Placeholder return ? placeholder.
+func (SQLite) Placeholder(n int) string {
+	return "?"
+}
+
+//Quote quote the identifier with double quote.
+func (SQLite) Quote(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
+
+//LimitOffset return LIMIT n OFFSET m clause, SQLite doesn't support OFFSET without LIMIT
+//so the negative limit is used when only the offset is specified.
+func (SQLite) LimitOffset(limit, offset int) string {
+	var query string
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	} else if offset > 0 {
+		query += " LIMIT -1"
+	}
+	if offset > 0 {
+		query += " OFFSET " + strconv.Itoa(offset)
+	}
+	return query
+}
+
+//Insert return an insert query, returning is ignored so the query works with SQLite
+//version before RETURNING is supported.
+func (d SQLite) Insert(table string, fields []string, rows int, returning []string) string {
+	return insertQuery(d, table, fields, rows)
+}
+
+//SupportReturning return false.
+func (SQLite) SupportReturning() bool {
+	return false
+}
+
+//Upsert return INSERT ... ON CONFLICT DO UPDATE query, or DO NOTHING when update is empty.
+func (d SQLite) Upsert(table string, fields []string, rows int, conflict, update []string) string {
+	query := insertQuery(d, table, fields, rows) + " ON CONFLICT (" + quoteFields(d, conflict) + ")"
+	if len(update) == 0 {
+		return query + " DO NOTHING"
+	}
+	set := make([]string, len(update))
+	for i, field := range update {
+		set[i] = d.Quote(field) + " = excluded." + d.Quote(field)
+	}
+	return query + " DO UPDATE SET " + strings.Join(set, ",")
+}
+
+//BatchLimit return 32766 args which is the default limit since SQLite 3.32.
+func (SQLite) BatchLimit() (args, rows int) {
+	return 32766, 0
+}
@@ -0,0 +1,945 @@
+package qb
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//openSQLiteDB create a SQLite database file on the test temporary directory
+//filled with the same data as preparePqTest.
+func openSQLiteDB(t testing.TB) (*sql.DB, []pqEmp) {
+	sdb, _ := openSQLiteTable(t, "emp", "ID varchar PRIMARY KEY,Name varchar,child numeric,joindate timestamp", pqEmp{})
+	data := []pqEmp{
+		{"A1", "AN1", 0, newTime(2010, time.January, 1)},
+		{"B2", "BN2", 1, newTime(2010, time.February, 2)},
+		{"C3", "CN3", 2, newTime(2010, time.March, 3)},
+		{"C4", "DN4", 3, newTime(2010, time.April, 3)},
+		{"D5", "DN5", 0, newTime(2010, time.January, 1)},
+		{"E6", "EN6", 1, newTime(2010, time.February, 2)},
+		{"F7", "FN7", 2, newTime(2010, time.March, 3)},
+		{"G8", "GN8", 3, newTime(2010, time.April, 3)},
+		{"H9", "HN9", 3, newTime(2010, time.April, 3)},
+	}
+	u := newSQLiteUpdateTest(t)
+	for i, v := range data {
+		if err := u.Insert(sdb, v); err != nil {
+			t.Fatalf("insert data: %d err: %v", i, err)
+		}
+	}
+	return sdb, data
+}
+
+//itemColumns is the columns of the item table for insertItem.
+const itemColumns = "ID integer PRIMARY KEY AUTOINCREMENT,Name varchar NOT NULL,Qty int"
+
+//openSQLiteTable return an empty database with the table created using the columns
+//and the Table of s.
+func openSQLiteTable(t testing.TB, name, columns string, s interface{}) (*sql.DB, Table) {
+	sdb, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "qb_test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite err: %v", err)
+	}
+	t.Cleanup(func() { sdb.Close() })
+	return sdb, createSQLiteTable(t, sdb, name, columns, s)
+}
+
+//createSQLiteTable create the table on sdb using the columns and return the Table of s.
+func createSQLiteTable(t testing.TB, sdb *sql.DB, name, columns string, s interface{}) Table {
+	if _, err := sdb.Exec("CREATE TABLE " + name + " (" + columns + ")"); err != nil {
+		t.Fatalf("create table err: %v", err)
+	}
+	tbl, err := NewTable(name, s)
+	if err != nil {
+		t.Fatalf("newTable err: %v", err)
+	}
+	return tbl
+}
+
+func newSQLiteSelectTest(t testing.TB) *Select {
+	tbl, err := NewTable("emp", pqEmp{})
+	if err != nil {
+		t.Errorf("create table err: %v", err)
+	}
+	return NewSQLiteSelect(tbl, true)
+}
+
+func newSQLiteUpdateTest(t testing.TB) *Update {
+	tbl, err := NewTable("emp", pqEmp{})
+	if err != nil {
+		t.Errorf("create table err: %v", err)
+	}
+	return NewSQLiteUpdate(tbl)
+}
+
+func TestSQLiteSetFilter(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	var tests = []struct {
+		field string
+		value interface{}
+		want  pqEmp
+	}{
+		{"id", "A1", data[0]},
+		{"name", "BN2", data[1]},
+		{"child", 2, data[2]},
+		{"joinDate", newTime(2010, time.April, 3), data[3]},
+	}
+	for _, test := range tests {
+		emp.Reset()
+		emp.SetFilter(test.field, "=", test.value)
+		list := NewList(emp)
+		if err := list.Get(sdb); err != nil {
+			t.Fatalf("list get err: %v", err)
+		}
+		got := pqEmp{}
+		if err := list.Next(&got); err != nil {
+			t.Fatalf("list next err: %v", err)
+		}
+		list.Close()
+		checkResult(t, emp, got, test.want)
+	}
+}
+
+func TestSQLiteGetByPK(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	got := pqEmp{}
+	if err := emp.GetByPK(sdb, &got, "A1"); err != nil {
+		t.Fatal(err)
+	}
+	checkResult(t, emp, got, data[0])
+	err := emp.GetByPK(sdb, &got, "XX")
+	if err != sql.ErrNoRows {
+		t.Errorf("got err: %v want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestSQLiteGetByPKWithCursor(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	start := 2
+	got := pqEmp{}
+	cursor, err := emp.GetByPKWithCursor(sdb, &got, "C3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResult(t, emp, got, data[start])
+	if err = emp.GetNext(sdb, &got, cursor); err != nil {
+		t.Fatalf("getNext err : %v", err)
+	}
+	checkResult(t, emp, got, data[start+1])
+	if err = emp.GetPrevious(sdb, &got, emp.Cursor()); err != nil {
+		t.Fatalf("getPrevious err : %v", err)
+	}
+	checkResult(t, emp, got, data[start])
+	if err = emp.GetLast(sdb, &got, emp.Cursor()); err != nil {
+		t.Fatalf("getLast err : %v", err)
+	}
+	checkResult(t, emp, got, data[len(data)-1])
+}
+
+func TestSQLiteGetByPKWithCursorExpr(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	emp.SetExpr("rank", NewExpr("? - child", 10)).SetFields("id", "rank").OrderBy("rank")
+	got := struct {
+		ID   string
+		Rank int
+	}{}
+	//the rows is ordered by child desc: C4,G8,H9,C3,F7,...
+	cursor, err := emp.GetByPKWithCursor(sdb, &got, "F7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "F7" || got.Rank != 8 {
+		t.Errorf("got: %v want {F7 8}", got)
+	}
+	c, err := Cursor{}.Decode(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.offset != 4 {
+		t.Errorf("got cursor offset: %d want 4", c.offset)
+	}
+}
+
+func TestSQLiteListNextAndLast(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	emp.SetLimit(2)
+	list := NewList(emp)
+	if err := list.Get(sdb); err != nil {
+		t.Fatalf("get list err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[:2])
+	if err := list.GetNext(sdb, emp.Cursor()); err != nil {
+		t.Fatalf("list GetNext err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[2:4])
+	if err := list.GetLast(sdb, emp.Cursor()); err != nil {
+		t.Fatalf("list GetLast err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[len(data)-2:])
+	if err := list.GetLast(sdb, emp.Cursor()); err != ErrDone {
+		t.Errorf("list GetLast got err: %v want ErrDone", err)
+	}
+}
+
+func checkSQLiteList(t *testing.T, emp *Select, list *List, wants []pqEmp) {
+	var gots []pqEmp
+	got := new(pqEmp)
+	var err error
+	for {
+		if err = list.Next(got); err != nil {
+			break
+		}
+		gots = append(gots, *got)
+	}
+	if err != ErrDone {
+		t.Fatalf("got err: %v want ErrDone", err)
+	}
+	if len(gots) != len(wants) {
+		t.Fatalf("gots len: %d want len %d", len(gots), len(wants))
+	}
+	for i, want := range wants {
+		checkResult(t, emp, gots[i], want)
+	}
+}
+
+func TestSQLiteListScanArger(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	emp.SetFilter("id", "=", "B2")
+	emp.SetFields("id", "name")
+	list := NewList(emp)
+	if err := list.Get(sdb); err != nil {
+		t.Fatalf("list get err: %v", err)
+	}
+	defer list.Close()
+	sa := new(pqEmpArger)
+	if err := list.Next(sa); err != nil {
+		t.Fatalf("list next err: %v", err)
+	}
+	checkResult(t, emp, sa.emp, data[1])
+}
+
+func TestSQLiteListGetAll(t *testing.T) {
+	sdb, want := openSQLiteDB(t)
+	tbl, err := NewTable("emp", pqEmpPtr{})
+	if err != nil {
+		t.Fatalf("newTable err: %v", err)
+	}
+	emp := NewSQLiteSelect(tbl, true)
+	list := NewList(emp)
+	dst := make([]pqEmp, 30)
+	if err = list.GetAll(sdb, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if len(dst) != len(want) {
+		t.Fatalf("got len: %d want %d", len(dst), len(want))
+	}
+	for i, got := range dst {
+		checkResult(t, emp, got, want[i])
+	}
+}
+
+func TestSQLiteUpdateAndDelete(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	empUpdate := newSQLiteUpdateTest(t)
+	empSelect := newSQLiteSelectTest(t)
+	empUpdate.Set("Name", "UCN4")
+	if err := empUpdate.UpdateByPK(sdb, "C3"); err != nil {
+		t.Fatal(err)
+	}
+	got := pqEmp{}
+	if err := empSelect.GetByPK(sdb, &got, "C3"); err != nil {
+		t.Fatal(err)
+	}
+	want := data[2]
+	want.Name = "UCN4"
+	checkResult(t, empSelect, got, want)
+
+	empUpdate.Set("Child", 9)
+	empUpdate.SetFilter("name", "=", "DN5")
+	if err := empUpdate.Update(sdb); err != nil {
+		t.Fatal(err)
+	}
+	if err := empSelect.GetByPK(sdb, &got, "D5"); err != nil {
+		t.Fatal(err)
+	}
+	want = data[4]
+	want.Child = 9
+	checkResult(t, empSelect, got, want)
+
+	if err := empUpdate.DeleteByPK(sdb, "A1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := empSelect.GetByPK(sdb, &got, "A1"); err != sql.ErrNoRows {
+		t.Errorf("got err: %v want %v", err, sql.ErrNoRows)
+	}
+}
+
+func TestSQLiteInFilterWithCursor(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	emp.SetFilter("id", "IN", []string{"B2", "C4", "F7", "XX"})
+	emp.SetFilter("child", "NOT IN", []int{3})
+	emp.SetLimit(1)
+	list := NewList(emp)
+	if err := list.Get(sdb); err != nil {
+		t.Fatalf("get list err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[1:2])
+	if err := list.GetNext(sdb, emp.Cursor()); err != nil {
+		t.Fatalf("list GetNext err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[6:7])
+}
+
+func TestSQLiteNullFilter(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	empUpdate := newSQLiteUpdateTest(t)
+	empUpdate.Set("name", nil)
+	if err := empUpdate.UpdateByPK(sdb, "E6"); err != nil {
+		t.Fatal(err)
+	}
+	emp := newSQLiteSelectTest(t)
+	emp.SetFields("id")
+	emp.SetFilter("name", "=", nil)
+	list := NewList(emp)
+	if err := list.Get(sdb); err != nil {
+		t.Fatalf("get list err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[5:6])
+
+	emp.Reset()
+	emp.SetFields("id")
+	emp.SetFilter("name", "<>", nil)
+	emp.SetLimit(4).SetOffset(1)
+	if err := list.GetNext(sdb, emp.Cursor()); err != nil {
+		t.Fatalf("list GetNext err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, data[len(data)-3:])
+}
+
+func TestSQLiteLikeFilter(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	empUpdate := newSQLiteUpdateTest(t)
+	empUpdate.Set("name", "50%_off")
+	if err := empUpdate.UpdateByPK(sdb, "C4"); err != nil {
+		t.Fatal(err)
+	}
+	want := data[3]
+	want.Name = "50%_off"
+	var tests = []struct {
+		cond  Condition
+		wants []pqEmp
+	}{
+		{Contains("name", "%_"), []pqEmp{want}},
+		{StartsWith("name", "5"), []pqEmp{want}},
+		{EndsWith("name", "N2"), data[1:2]},
+		{Filter("name", "ILIKE", "fn%"), data[6:7]},
+	}
+	emp := newSQLiteSelectTest(t)
+	for _, test := range tests {
+		emp.Reset()
+		emp.Where(test.cond)
+		list := NewList(emp)
+		if err := list.Get(sdb); err != nil {
+			t.Fatalf("get list err: %v", err)
+		}
+		checkSQLiteList(t, emp, list, test.wants)
+	}
+}
+
+func TestSQLiteOrderByDesc(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	emp := newSQLiteSelectTest(t)
+	emp.OrderBy(Desc("joindate"), Asc("id"))
+	emp.SetLimit(3)
+	list := NewList(emp)
+	if err := list.Get(sdb); err != nil {
+		t.Fatalf("get list err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, []pqEmp{data[3], data[7], data[8]})
+	if err := list.GetNext(sdb, emp.Cursor()); err != nil {
+		t.Fatalf("list GetNext err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, []pqEmp{data[2], data[6], data[1]})
+	if err := list.GetLast(sdb, emp.Cursor()); err != nil {
+		t.Fatalf("list GetLast err: %v", err)
+	}
+	checkSQLiteList(t, emp, list, []pqEmp{data[5], data[0], data[4]})
+
+	got := pqEmp{}
+	emp.SetLimit(1).SetOffset(0)
+	if err := emp.GetLast(sdb, &got, emp.Cursor()); err != nil {
+		t.Fatalf("getLast err : %v", err)
+	}
+	checkResult(t, emp, got, data[4])
+	if err := emp.GetPrevious(sdb, &got, emp.Cursor()); err != nil {
+		t.Fatalf("getPrevious err : %v", err)
+	}
+	checkResult(t, emp, got, data[0])
+}
+
+type sqliteEmpDept struct {
+	ID   string
+	Name string
+	Dept struct {
+		ID   string
+		Name string
+	}
+	DeptCode string
+}
+
+func TestSQLiteJoin(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	type Dept struct {
+		ID   string `pk:"1"`
+		Name string
+		Code string
+		Head string
+	}
+	dept := createSQLiteTable(t, sdb, "dept", "ID varchar PRIMARY KEY,Name varchar,Code varchar,Head varchar", Dept{})
+	u := NewSQLiteUpdate(dept)
+	for _, v := range []Dept{{"D1", "IT", "it", "B2"}, {"D2", "HR", "hr", "F7"}} {
+		if err := u.Insert(sdb, v); err != nil {
+			t.Fatalf("insert dept err: %v", err)
+		}
+	}
+	emp := newSQLiteSelectTest(t)
+	emp.As("e").Join(dept, "dept", On("dept.head", "e.id"))
+	emp.SetFields("e.id", "e.name", "dept.id", "dept.name", "dept.code")
+	emp.OrderBy(Desc("dept.name"))
+	list := NewList(emp)
+	var got []sqliteEmpDept
+	if err := list.GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got len: %d want 2", len(got))
+	}
+	want := sqliteEmpDept{ID: data[1].ID, Name: data[1].Name, DeptCode: "it"}
+	want.Dept.ID, want.Dept.Name = "D1", "IT"
+	if got[0] != want {
+		t.Errorf("got: %v want %v", got[0], want)
+	}
+	want = sqliteEmpDept{ID: data[6].ID, Name: data[6].Name, DeptCode: "hr"}
+	want.Dept.ID, want.Dept.Name = "D2", "HR"
+	if got[1] != want {
+		t.Errorf("got: %v want %v", got[1], want)
+	}
+
+	emp.Reset()
+	emp.SetFields("e.id", "dept.name AS deptname")
+	emp.SetFilter("dept.code", "=", "hr")
+	gotPK := struct {
+		ID       string
+		DeptName string
+	}{}
+	if err := emp.GetByPK(sdb, &gotPK, "F7"); err != nil {
+		t.Fatal(err)
+	}
+	if gotPK.ID != "F7" || gotPK.DeptName != "HR" {
+		t.Errorf("got: %v want {F7 HR}", gotPK)
+	}
+	cursor, err := emp.GetByPKWithCursor(sdb, &gotPK, "F7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Cursor{}.Decode(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.offset != 1 {
+		t.Errorf("got cursor offset: %d want 1", c.offset)
+	}
+
+	//the unmatched row of LEFT JOIN leave the field zero and the pointer nil.
+	emp = newSQLiteSelectTest(t)
+	emp.As("e").LeftJoin(dept, "dept", On("dept.head", "e.id"))
+	emp.SetFields("e.id", "dept.name", "dept.code").SetFilter("e.id", "IN", []string{"A1", "B2"})
+	var left []struct {
+		ID   string
+		Dept struct {
+			Name string
+		}
+		DeptCode *string
+	}
+	if err := NewList(emp).GetAll(sdb, &left); err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 2 {
+		t.Fatalf("got len: %d want 2", len(left))
+	}
+	if left[0].ID != "A1" || left[0].Dept.Name != "" || left[0].DeptCode != nil {
+		t.Errorf("got: %v want {A1 {} <nil>}", left[0])
+	}
+	if left[1].ID != "B2" || left[1].Dept.Name != "IT" || left[1].DeptCode == nil || *left[1].DeptCode != "it" {
+		t.Errorf("got: %v want B2 with dept IT and code it", left[1])
+	}
+}
+
+func TestSQLiteGroupBy(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	s := newSQLiteSelectTest(t)
+	s.SetFields("child", Count("*", "n"), Min("id", "first")).
+		GroupBy("child").
+		Having(Filter(Count("*", ""), ">=", 2)).
+		OrderBy(Desc("n"), "child")
+	var got []struct {
+		Child int
+		N     int
+		First string
+	}
+	if err := NewList(s).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("got len: %d want 4", len(got))
+	}
+	if got[0].Child != 3 || got[0].N != 3 || got[0].First != "C4" {
+		t.Errorf("got: %v want {3 3 C4}", got[0])
+	}
+	if got[1].Child != 0 || got[1].N != 2 || got[1].First != "A1" {
+		t.Errorf("got: %v want {0 2 A1}", got[1])
+	}
+
+	s.Having(Filter(Count("*", ""), ">", 2))
+	count, err := s.getCount(sdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("got count: %d want 1", count)
+	}
+}
+
+func TestSQLiteSubquery(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	inner := newSQLiteSelectTest(t).SetFields("child", Count("*", "n")).GroupBy("child")
+	s := NewSQLiteSelect(SubQuery(inner, "t"), false)
+	s.SetFilter("n", ">", 2)
+	var got []struct {
+		Child int
+		N     int
+	}
+	if err := NewList(s).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Child != 3 || got[0].N != 3 {
+		t.Errorf("got: %v want [{3 3}]", got)
+	}
+
+	other := newSQLiteSelectTest(t).As("o").SetFields("o.id").
+		Where(Filter("o.child", "=", Col("emp.child")), Filter("o.id", "<>", Col("emp.id")), Filter("o.name", ">", "D"))
+	s = newSQLiteSelectTest(t).SetFields("id").SetFilter("name", "<", "D").Where(Exists(other))
+	var ids []struct{ ID string }
+	if err := NewList(s).GetAll(sdb, &ids); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0].ID != "A1" || ids[1].ID != "B2" || ids[2].ID != "C3" {
+		t.Errorf("got: %v want [{A1} {B2} {C3}]", ids)
+	}
+}
+
+func TestSQLiteWithRecursive(t *testing.T) {
+	sdb, _ := openSQLiteTable(t, "node", "ID varchar PRIMARY KEY,ParentID varchar,Name varchar", cteNode{})
+	nodes := [][]string{
+		{"a", "", "root"}, {"b", "a", "b"}, {"c", "a", "c"}, {"d", "b", "d"},
+		{"e", "d", "e"}, {"f", "x", "f"}, {"g", "c", "skip"}, {"h", "g", "h"},
+	}
+	for _, n := range nodes {
+		if _, err := sdb.Exec("INSERT INTO node VALUES (?,?,?)", n[0], n[1], n[2]); err != nil {
+			t.Fatalf("insert node err: %v", err)
+		}
+	}
+	anchor, recursive, tree := newCTERecursive(t, SQLite{})
+	anchor.Reset()
+	anchor.SetFields("id", "parentid", "name").SetFilter("id", "=", "a")
+	s := NewSQLiteSelect(tree, false).WithRecursive("tree", anchor, recursive).SetLimit(2)
+	list := NewList(s)
+	if err := list.Get(sdb); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	got := new(cteNode)
+	for list.Next(got) == nil {
+		ids = append(ids, got.ID)
+	}
+	if err := list.GetNext(sdb, s.Cursor()); err != nil {
+		t.Fatal(err)
+	}
+	for list.Next(got) == nil {
+		ids = append(ids, got.ID)
+	}
+	if err := list.GetNext(sdb, s.Cursor()); err != nil {
+		t.Fatal(err)
+	}
+	for list.Next(got) == nil {
+		ids = append(ids, got.ID)
+	}
+	if strings.Join(ids, ",") != "a,b,c,d,e" {
+		t.Errorf("got ids: %v want [a b c d e]", ids)
+	}
+
+	cursor, err := s.GetByPKWithCursor(sdb, got, "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ParentID != "b" {
+		t.Errorf("got parentid: %s want b", got.ParentID)
+	}
+	c, err := Cursor{}.Decode(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.offset != 3 {
+		t.Errorf("got cursor offset: %d want 3", c.offset)
+	}
+	s.SetOffset(0)
+	if err := list.GetLast(sdb, s.Cursor()); err != nil {
+		t.Fatal(err)
+	}
+	ids = ids[:0]
+	for list.Next(got) == nil {
+		ids = append(ids, got.ID)
+	}
+	if strings.Join(ids, ",") != "d,e" {
+		t.Errorf("got last ids: %v want [d e]", ids)
+	}
+}
+
+func TestSQLiteScanMissingField(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	var got []struct {
+		ID   string
+		Name string
+	}
+	err := NewList(newSQLiteSelectTest(t)).GetAll(sdb, &got)
+	if err == nil || err.Error() != "field child doesn't exist on dst" {
+		t.Errorf("got err: %v want field child doesn't exist on dst", err)
+	}
+}
+
+func TestSQLiteUnion(t *testing.T) {
+	sdb, data := openSQLiteDB(t)
+	tbl := createSQLiteTable(t, sdb, "archived", "ID varchar PRIMARY KEY,Name varchar,child numeric,joindate timestamp", pqEmp{})
+	archived := []pqEmp{
+		{"Z1", "ZN1", 5, newTime(2009, time.January, 1)},
+		{"Z2", "ZN2", 0, newTime(2009, time.February, 2)},
+	}
+	u := NewSQLiteUpdate(tbl)
+	for _, v := range archived {
+		if err := u.Insert(sdb, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emp := newSQLiteSelectTest(t).SetFilter("child", ">=", 3)
+	emp.UnionAll(NewSQLiteSelect(tbl, true).SetFilter("child", "IN", []int{0, 5}))
+	emp.OrderBy(Desc("child")).SetLimit(2)
+	list := NewList(emp)
+	if err := list.Get(sdb); err != nil {
+		t.Fatal(err)
+	}
+	checkSQLiteList(t, emp, list, []pqEmp{archived[0], data[3]})
+	if err := list.GetNext(sdb, emp.Cursor()); err != nil {
+		t.Fatal(err)
+	}
+	checkSQLiteList(t, emp, list, []pqEmp{data[7], data[8]})
+	if err := list.GetLast(sdb, emp.Cursor()); err != nil {
+		t.Fatal(err)
+	}
+	checkSQLiteList(t, emp, list, []pqEmp{data[8], archived[1]})
+
+	var all []pqEmp
+	emp.SetOffset(0).SetLimit(0)
+	if err := NewList(emp).GetAll(sdb, &all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Errorf("got len: %d want 5", len(all))
+	}
+}
+
+func TestSQLiteDistinct(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	s := newSQLiteSelectTest(t).SetFields("child").Distinct().OrderBy(Desc("child")).SetLimit(3)
+	var got []struct{ Child int }
+	if err := NewList(s).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Child != 3 || got[1].Child != 2 || got[2].Child != 1 {
+		t.Errorf("got: %v want [{3} {2} {1}]", got)
+	}
+	count, err := s.getCount(sdb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 4 {
+		t.Errorf("got count: %d want 4", count)
+	}
+}
+
+func TestSQLiteExpr(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	s := newSQLiteSelectTest(t).
+		SetExpr("lname", NewExpr("lower(name)")).
+		SetExpr("kids", NewExpr("child * ?", 10))
+	s.SetFields("id", "lname", "kids").SetFilter("lname", "LIKE", "d%").OrderBy(Desc("kids"))
+	var got []struct {
+		ID    string
+		LName string
+		Kids  int
+	}
+	if err := NewList(s).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "C4" || got[0].LName != "dn4" || got[0].Kids != 30 || got[1].ID != "D5" {
+		t.Errorf("got: %v want [{C4 dn4 30} {D5 dn5 0}]", got)
+	}
+
+	u := newSQLiteUpdateTest(t)
+	u.Set("child", NewExpr("child + ?", 2))
+	u.SetFilter("id", "=", "D5")
+	if err := u.Update(sdb); err != nil {
+		t.Fatal(err)
+	}
+	var child int
+	if err := sdb.QueryRow("SELECT child FROM emp WHERE id = 'D5'").Scan(&child); err != nil {
+		t.Fatal(err)
+	}
+	if child != 2 {
+		t.Errorf("got child: %d want 2", child)
+	}
+}
+
+func TestSQLiteUpdateIncrement(t *testing.T) {
+	sdb, _ := openSQLiteDB(t)
+	u := newSQLiteUpdateTest(t)
+	u.Set("child", Increment(2))
+	u.Set("name", Func("lower", "X"))
+	if err := u.UpdateByPK(sdb, "H9"); err != nil {
+		t.Fatal(err)
+	}
+	u.Set("child", Decrement(1))
+	u.Set("name", Col("id"))
+	u.SetFilter("id", "IN", []string{"A1", "B2"})
+	if err := u.Update(sdb); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := sdb.Query("SELECT id, name, child FROM emp WHERE id IN ('A1','B2','H9') ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var id, name string
+		var child int
+		if err := rows.Scan(&id, &name, &child); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, id+" "+name+" "+strconv.Itoa(child))
+	}
+	want := "A1 A1 -1,B2 B2 0,H9 x 5"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got: %v want %s", got, want)
+	}
+}
+
+func TestSQLiteInsertReturning(t *testing.T) {
+	sdb, tbl := openSQLiteTable(t, "item", itemColumns, insertItem{})
+	u := NewSQLiteUpdate(tbl)
+	var got insertItem
+	for i, name := range []string{"a", "b"} {
+		if err := u.InsertReturning(sdb, insertItem{Name: name, Qty: 3}, &got); err != nil {
+			t.Fatal(err)
+		}
+		if want := (insertItem{int64(i + 1), name, 3}); got != want {
+			t.Errorf("got: %v want %v", got, want)
+		}
+	}
+	if err := u.InsertReturning(sdb, &insertItem{ID: 10, Name: "c"}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (insertItem{10, "c", 0}); got != want {
+		t.Errorf("got: %v want %v", got, want)
+	}
+	if err := u.InsertReturning(sdb, insertItem{ID: 10, Name: "d"}, &got); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+}
+
+func TestSQLiteInsertReturningNaturalKey(t *testing.T) {
+	type code struct {
+		Code int `pk:"1"`
+		Name string
+	}
+	sdb, tbl := openSQLiteTable(t, "code", "Code int PRIMARY KEY,Name varchar NOT NULL", code{})
+	u := NewSQLiteUpdate(tbl)
+	var got code
+	//0 is a valid key as the primary key is not tagged with qb:"default".
+	for _, src := range []code{{5, "five"}, {0, "zero"}} {
+		if err := u.InsertReturning(sdb, src, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != src {
+			t.Errorf("got: %v want %v", got, src)
+		}
+	}
+}
+
+func TestSQLiteInsertMany(t *testing.T) {
+	sdb, tbl := openSQLiteTable(t, "item", itemColumns, insertItem{})
+	var items []*insertItem
+	for i := 1; i <= 5; i++ {
+		items = append(items, &insertItem{ID: int64(i), Name: "n" + strconv.Itoa(i), Qty: i})
+	}
+	var progress []int
+	u := NewUpdate(smallBatch{}, tbl).OnProgress(func(inserted, total int) {
+		if total != 5 {
+			t.Errorf("got total: %d want 5", total)
+		}
+		progress = append(progress, inserted)
+	})
+	affected, err := u.InsertMany(sdb, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != 5 {
+		t.Errorf("got affected: %d want 5", affected)
+	}
+	if !reflect.DeepEqual(progress, []int{2, 4, 5}) {
+		t.Errorf("got progress: %v want [2 4 5]", progress)
+	}
+	var got []insertItem
+	if err := NewList(NewSQLiteSelect(tbl, true)).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 5 || got[4] != *items[4] {
+		t.Errorf("got: %v want 5 items", got)
+	}
+
+	//the chunk before the failed chunk is inserted.
+	u.OnProgress(nil)
+	affected, err = u.InsertMany(sdb, []insertItem{{ID: 6, Name: "a"}, {ID: 7, Name: "b"}, {ID: 1, Name: "c"}})
+	if err == nil || affected != 2 {
+		t.Errorf("got affected: %d err: %v want 2 and an error", affected, err)
+	}
+	if _, err := u.InsertMany(sdb, insertItem{}); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+}
+
+func TestSQLiteLoad(t *testing.T) {
+	sdb, tbl := openSQLiteTable(t, "item", itemColumns, insertItem{})
+	copyIn := func(table string, columns ...string) string {
+		t.Errorf("copyIn is called for SQLite")
+		return ""
+	}
+	tx, err := sdb.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	u := NewUpdate(smallBatch{}, tbl)
+	n, err := u.Load(tx, copyIn, []insertItem{{1, "a", 1}, {2, "b", 2}, {3, "c", 3}})
+	if err != nil || n != 3 {
+		t.Fatalf("got n: %d err: %v want 3 and nil", n, err)
+	}
+	rows := make(chan *insertItem)
+	go func() {
+		for i := 4; i <= 8; i++ {
+			rows <- &insertItem{int64(i), "n", i}
+		}
+		close(rows)
+	}()
+	if n, err = u.Load(tx, nil, rows); err != nil || n != 5 {
+		t.Fatalf("got n: %d err: %v want 5 and nil", n, err)
+	}
+	var count int
+	if err := tx.QueryRow("SELECT count(*), sum(qty) FROM item").Scan(&count, &n); err != nil {
+		t.Fatal(err)
+	}
+	if count != 8 || n != 36 {
+		t.Errorf("got count: %d sum: %d want 8 and 36", count, n)
+	}
+	if _, err := u.Load(tx, nil, insertItem{}); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+}
+
+func TestSQLiteUpsert(t *testing.T) {
+	sdb, tbl := openSQLiteTable(t, "item", itemColumns, insertItem{})
+	u := NewSQLiteUpdate(tbl)
+	for _, item := range []insertItem{{1, "a", 1}, {2, "b", 2}, {1, "c", 3}} {
+		if err := u.Upsert(sdb, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u.DoUpdate("qty")
+	if err := u.Upsert(sdb, &insertItem{2, "d", 4}); err != nil {
+		t.Fatal(err)
+	}
+	u.DoNothing()
+	if err := u.Upsert(sdb, insertItem{1, "e", 5}); err != nil {
+		t.Fatal(err)
+	}
+	var got []insertItem
+	if err := NewList(NewSQLiteSelect(tbl, true)).GetAll(sdb, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []insertItem{{1, "c", 3}, {2, "b", 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v want %v", got, want)
+	}
+}
+
+func TestSQLiteInsertPartial(t *testing.T) {
+	columns := "ID integer PRIMARY KEY AUTOINCREMENT,Name varchar NOT NULL,Status varchar NOT NULL DEFAULT 'new'," +
+		"Note varchar DEFAULT 'none',CreatedAt datetime NOT NULL DEFAULT '2020-01-02 00:00:00'"
+	sdb, tbl := openSQLiteTable(t, "item", columns, partialItem{})
+	u := NewSQLiteUpdate(tbl)
+	note := "x"
+	for _, item := range []partialItem{{Name: "a"}, {Name: "b", Status: "done", Note: &note}} {
+		if err := u.InsertPartial(sdb, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows, err := sdb.Query("SELECT id, name, status, note FROM item ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var id int
+		var name, status, note string
+		if err := rows.Scan(&id, &name, &status, &note); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, strconv.Itoa(id)+" "+name+" "+status+" "+note)
+	}
+	want := "1 a new none,2 b done x"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got: %v want %s", got, want)
+	}
+
+	inserted := partialItem{Note: new(string)}
+	if err := u.InsertReturning(sdb, partialItem{Name: "d"}, &inserted); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	if inserted.ID != 3 || inserted.Status != "new" || *inserted.Note != "none" || !inserted.CreatedAt.Equal(at) {
+		t.Errorf("got: %v want the default values", inserted)
+	}
+}
@@ -0,0 +1,106 @@
+package qb
+
+import (
+	"strconv"
+	"strings"
+)
+
+//SQLServer is a Dialect for Microsoft SQL Server database.
+type SQLServer struct{}
+
+//Placeholder return @pn placeholder.
+func (SQLServer) Placeholder(n int) string {
+	return "@p" + strconv.Itoa(n)
+}
+
+//Quote quote the identifier with bracket.
+func (SQLServer) Quote(identifier string) string {
+	return quoteIdentifier(identifier, "[", "]")
+}
+
+//LimitOffset return OFFSET m ROWS FETCH NEXT n ROWS ONLY clause, the clause need ORDER BY
+//which is always rendered by Select.
+//When only the limit is specified the rows is limited by TOP, see Top.
+func (SQLServer) LimitOffset(limit, offset int) string {
+	if offset <= 0 {
+		return ""
+	}
+	query := " OFFSET " + strconv.Itoa(offset) + " ROWS"
+	if limit > 0 {
+		query += " FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY"
+	}
+	return query
+}
+
+//Fetch return OFFSET 0 ROWS FETCH NEXT n ROWS ONLY clause.
+func (SQLServer) Fetch(limit int) string {
+	return " OFFSET 0 ROWS FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY"
+}
+
+//Recursive return empty string, SQL Server use WITH for the recursive common table expression.
+func (SQLServer) Recursive() string {
+	return ""
+}
+
+//Top return TOP n clause that is rendered after SELECT when only the limit is specified.
+func (SQLServer) Top(limit, offset int) string {
+	if limit <= 0 || offset > 0 {
+		return ""
+	}
+	return "TOP " + strconv.Itoa(limit) + " "
+}
+
+//Insert return an insert query with OUTPUT clause when returning is not empty.
+func (d SQLServer) Insert(table string, fields []string, rows int, returning []string) string {
+	query := "INSERT INTO " + d.Quote(table) + " (" + quoteFields(d, fields) + ")"
+	if len(returning) > 0 {
+		output := make([]string, len(returning))
+		for i, field := range returning {
+			output[i] = "INSERTED." + d.Quote(field)
+		}
+		query += " OUTPUT " + strings.Join(output, ",")
+	}
+	return query + valuesQuery(d, len(fields), rows)
+}
+
+//SupportReturning return true.
+func (SQLServer) SupportReturning() bool {
+	return true
+}
+
+//Upsert return MERGE query that insert the rows which doesn't match with conflict fields
+//and update the update fields of the matched rows.
+func (d SQLServer) Upsert(table string, fields []string, rows int, conflict, update []string) string {
+	on := make([]string, len(conflict))
+	for i, field := range conflict {
+		on[i] = "target." + d.Quote(field) + " = source." + d.Quote(field)
+	}
+	source := make([]string, len(fields))
+	for i, field := range fields {
+		source[i] = "source." + d.Quote(field)
+	}
+	query := "MERGE INTO " + d.Quote(table) + " AS target USING (" +
+		strings.TrimPrefix(valuesQuery(d, len(fields), rows), " ") +
+		") AS source (" + quoteFields(d, fields) + ") ON " + strings.Join(on, " AND ")
+	if len(update) > 0 {
+		set := make([]string, len(update))
+		for i, field := range update {
+			set[i] = d.Quote(field) + " = source." + d.Quote(field)
+		}
+		query += " WHEN MATCHED THEN UPDATE SET " + strings.Join(set, ",")
+	}
+	query += " WHEN NOT MATCHED THEN INSERT (" + quoteFields(d, fields) + ") VALUES (" +
+		strings.Join(source, ",") + ");"
+	return query
+}
+
+//Order return field [DESC], NULLS FIRST and NULLS LAST is emulated
+//as SQL Server doesn't support it.
+func (SQLServer) Order(field string, desc bool, nulls string) string {
+	return nullsOrder(field, desc, nulls)
+}
+
+//BatchLimit return 2100 args and 1000 rows of the VALUES clause.
+func (SQLServer) BatchLimit() (args, rows int) {
+	return 2100, 1000
+}
@@ -0,0 +1,60 @@
+package qb
+
+import "testing"
+
+func TestSQLServerSelectQuery(t *testing.T) {
+	type Emp struct {
+		ID   string `pk:"1"`
+		Name string
+		Age  int
+	}
+	tbl, err := NewTable("", Emp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	b := NewSQLServerSelect(tbl, true)
+	b.SetRange("Name", "a", "b")
+	wantA := []interface{}{"a", "b"}
+	wantQ := "SELECT [id],[name],[age] FROM [emp] WHERE [name] >= @p1 AND [name] <= @p2 ORDER BY [id]"
+	testQuery(t, b, wantQ, wantA)
+	b.SetLimit(5)
+	wantQ = "SELECT TOP 5 [id],[name],[age] FROM [emp] WHERE [name] >= @p1 AND [name] <= @p2 ORDER BY [id]"
+	testQuery(t, b, wantQ, wantA)
+	b.SetOffset(10)
+	wantQ = "SELECT [id],[name],[age] FROM [emp] WHERE [name] >= @p1 AND [name] <= @p2 ORDER BY [id]" +
+		" OFFSET 10 ROWS FETCH NEXT 5 ROWS ONLY"
+	testQuery(t, b, wantQ, wantA)
+	b.SetLimit(0)
+	wantQ = "SELECT [id],[name],[age] FROM [emp] WHERE [name] >= @p1 AND [name] <= @p2 ORDER BY [id] OFFSET 10 ROWS"
+	testQuery(t, b, wantQ, wantA)
+}
+
+func TestSQLServerInsert(t *testing.T) {
+	d := SQLServer{}
+	got := d.Insert("emp", []string{"id", "name"}, 2, []string{"id"})
+	want := "INSERT INTO [emp] ([id],[name]) OUTPUT INSERTED.[id] VALUES (@p1,@p2),(@p3,@p4)"
+	if got != want {
+		t.Errorf("got: %s\n        want %s", got, want)
+	}
+}
+
+func TestSQLServerUpsert(t *testing.T) {
+	d := SQLServer{}
+	var tests = []struct {
+		update []string
+		want   string
+	}{
+		{nil, "MERGE INTO [emp] AS target USING (VALUES (@p1,@p2)) AS source ([id],[name])" +
+			" ON target.[id] = source.[id]" +
+			" WHEN NOT MATCHED THEN INSERT ([id],[name]) VALUES (source.[id],source.[name]);"},
+		{[]string{"name"}, "MERGE INTO [emp] AS target USING (VALUES (@p1,@p2)) AS source ([id],[name])" +
+			" ON target.[id] = source.[id] WHEN MATCHED THEN UPDATE SET [name] = source.[name]" +
+			" WHEN NOT MATCHED THEN INSERT ([id],[name]) VALUES (source.[id],source.[name]);"},
+	}
+	for _, test := range tests {
+		got := d.Upsert("emp", []string{"id", "name"}, 1, []string{"id"}, test.update)
+		if got != test.want {
+			t.Errorf("got: %s\n        want %s", got, test.want)
+		}
+	}
+}
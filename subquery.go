@@ -0,0 +1,104 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	opExists    = "EXISTS"
+	opNotExists = "NOT EXISTS"
+)
+
+//Exists return a condition that is true when the subquery s return any row.
+//The placeholder of the subquery is numbered after the placeholder of the outer query.
+//	Exists(NewPQSelect(dept, false).SetFilter("head", "=", Col("emp.id")))
+func Exists(s *Select) Condition {
+	return filter{op: opExists, value: s}
+}
+
+//NotExists return a condition that is true when the subquery s doesn't return any row.
+func NotExists(s *Select) Condition {
+	return filter{op: opNotExists, value: s}
+}
+
+func isExistsOp(op string) bool {
+	return op == opExists || op == opNotExists
+}
+
+type derived struct {
+	s     *Select
+	alias string
+}
+
+//SubQuery return a table from the query of s to use as the table of the other select or the join,
+//the fields of the table is the fields selected by s. The alias is required to name the table.
+//ORDER BY of s is only rendered when s is limited.
+//	inner := NewPQSelect(emp, false).SetFields("deptid", Count("*", "n")).GroupBy("deptid")
+//	NewPQSelect(SubQuery(inner, "t"), false).SetFilter("n", ">", 5)
+func SubQuery(s *Select, alias string) Tabler {
+	return derived{s: s, alias: strings.ToLower(alias)}
+}
+
+//TableName return the alias of the subquery.
+func (t derived) TableName() string {
+	return t.alias
+}
+
+//Fields return the column names of the subquery.
+func (t derived) Fields() []string {
+	fields := t.s.scanFields()
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		_, names[i] = splitQualifier(scanName(field))
+	}
+	return names
+}
+
+//PrimaryKeys return the primary keys of the subquery table when they are selected,
+//or the group by fields when the subquery is grouped.
+func (t derived) PrimaryKeys() []string {
+	if len(t.s.groupBy) != 0 {
+		pks := make([]string, len(t.s.groupBy))
+		for i, field := range t.s.groupBy {
+			_, pks[i] = splitQualifier(field)
+		}
+		return pks
+	}
+	pks := t.s.t.PrimaryKeys()
+	for _, pk := range pks {
+		if !isFieldExist(t, pk) {
+			return nil
+		}
+	}
+	return pks
+}
+
+//tableQuery return the table or the subquery with the alias to use in FROM and JOIN clause.
+func tableQuery(d Dialect, t Tabler, alias string, starting int) (query string, args []interface{}, next int) {
+	sub, ok := t.(derived)
+	if !ok {
+		query = d.Quote(t.TableName())
+		if alias != "" {
+			query += " AS " + d.Quote(alias)
+		}
+		return query, nil, starting
+	}
+	if alias == "" {
+		alias = sub.alias
+	}
+	query, args, next = sub.s.nestedQuery(starting)
+	return "(" + query + ") AS " + d.Quote(alias), args, next
+}
+
+//derivedError return the error of the query of the subquery table used in FROM or JOIN clause.
+func derivedError(t Tabler) error {
+	sub, ok := t.(derived)
+	if !ok {
+		return nil
+	}
+	if err := sub.s.Error(); err != nil {
+		return fmt.Errorf("subquery %s: %v", sub.alias, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package qb
+
+import "testing"
+
+func newSubqueryTables(t *testing.T) (emp, dept Table) {
+	emp, err := NewTable("emp", joinEmp{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	dept, err = NewTable("dept", joinDept{})
+	if err != nil {
+		t.Fatalf("got err: %v want nil", err)
+	}
+	return emp, dept
+}
+
+func TestSubqueryFilter(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	sub := NewPQSelect(dept, false).SetFields("id").SetFilter("name", "LIKE", "i%")
+	b := NewPQSelect(emp, false)
+	b.SetFilter("name", "<>", "al").SetFilter("deptid", "IN", sub).SetFilter("id", ">", "a")
+	wantQ := "SELECT * FROM emp WHERE name <> $1 AND deptid IN (SELECT id FROM dept WHERE name LIKE $2)" +
+		" AND id > $3 ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"al", "i%", "a"})
+	testError(t, b, false)
+
+	b.Reset()
+	sub = NewPQSelect(dept, false).SetFields("id").Where(Filter("id", "=", Col("emp.deptid")), Filter("name", "=", "it"))
+	b.SetFilter("name", "=", "al").Where(Not(Exists(sub)))
+	wantQ = "SELECT * FROM emp WHERE name = $1 AND NOT (EXISTS (SELECT id FROM dept WHERE id = emp.deptid AND" +
+		" name = $2)) ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"al", "it"})
+	testError(t, b, false)
+}
+
+func TestSubqueryFilterError(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	b := NewPQSelect(emp, false)
+	b.Where(Exists(NewPQSelect(dept, false).SetFilter("notexist", "=", 1)))
+	testError(t, b, true)
+
+	b.Reset()
+	b.Where(Exists(NewPQSelect(dept, false).SetFilter("id", "=", Col("emp.notexist"))))
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFilter("notexist", "IN", NewPQSelect(dept, false).SetFields("id"))
+	testError(t, b, true)
+
+	b.Reset()
+	b.SetFilter("id", "EXISTS", 1)
+	testError(t, b, true)
+}
+
+func TestSubqueryTable(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	inner := NewMySQLSelect(emp, false).SetFields("deptid", Count("*", "n")).SetFilter("name", "<>", "al").GroupBy("deptid")
+	b := NewMySQLSelect(SubQuery(inner, "T"), false)
+	b.SetFilter("n", ">", 2).SetLimit(5)
+	wantQ := "SELECT * FROM (SELECT `deptid`,count(*) AS `n` FROM `emp` WHERE `name` <> ?" +
+		" GROUP BY `deptid`) AS `t` WHERE `n` > ? ORDER BY `deptid` LIMIT 5"
+	testQuery(t, b, wantQ, []interface{}{"al", 2})
+	testError(t, b, false)
+
+	inner = NewPQSelect(emp, false).SetFields("deptid", Count("*", "n")).SetFilter("name", "<>", "al").GroupBy("deptid")
+	b = NewPQSelect(dept, false).As("d").Join(SubQuery(inner, "t"), "", On("t.deptid", "d.id"))
+	b.SetFields("d.name", "t.n").SetFilter("t.n", ">", 2)
+	wantQ = "SELECT d.name,t.n FROM dept AS d INNER JOIN (SELECT deptid,count(*) AS n FROM emp WHERE name <> $1" +
+		" GROUP BY deptid) AS t ON t.deptid = d.id WHERE t.n > $2 ORDER BY d.id"
+	testQuery(t, b, wantQ, []interface{}{"al", 2})
+	testError(t, b, false)
+
+	b = NewPQSelect(SubQuery(NewPQSelect(emp, false).SetFields("name"), "t"), false)
+	wantQ = "SELECT * FROM (SELECT name FROM emp) AS t"
+	testQuery(t, b, wantQ, nil)
+	b.SetFilter("id", "=", "a")
+	testError(t, b, true)
+
+	//the query of the subquery table is checked too.
+	inner = NewPQSelect(emp, false).SetFields("deptid").SetFilter("notexist", "=", 1)
+	b = NewPQSelect(SubQuery(inner, "t"), false)
+	testError(t, b, true)
+	b = NewPQSelect(dept, false).As("d").Join(SubQuery(inner, "t"), "", On("t.deptid", "d.id"))
+	testError(t, b, true)
+}
+
+func TestSubqueryNumbering(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	b := NewPQSelect(emp, false)
+	b.SetFilter("deptid", "IN", NewPQSelect(dept, false).SetFields("id")).SetFilter("name", "<>", "al")
+	wantQ := "SELECT * FROM emp WHERE deptid IN (SELECT id FROM dept) AND name <> $1 ORDER BY id"
+	testQuery(t, b, wantQ, []interface{}{"al"})
+
+	inner := NewPQSelect(emp, false).SetFields("deptid", Count("*", "n")).GroupBy("deptid")
+	b = NewPQSelect(SubQuery(inner, "t"), false).SetFilter("n", ">", 5)
+	wantQ = "SELECT * FROM (SELECT deptid,count(*) AS n FROM emp GROUP BY deptid) AS t" +
+		" WHERE n > $1 ORDER BY deptid"
+	testQuery(t, b, wantQ, []interface{}{5})
+
+	b = NewPQSelect(dept, false).As("d").Join(SubQuery(inner, "t"), "", On("t.deptid", "d.id"))
+	b.SetFields("d.name", "t.n").SetFilter("d.name", "=", "it")
+	wantQ = "SELECT d.name,t.n FROM dept AS d INNER JOIN (SELECT deptid,count(*) AS n FROM emp GROUP BY deptid)" +
+		" AS t ON t.deptid = d.id WHERE d.name = $1 ORDER BY d.id"
+	testQuery(t, b, wantQ, []interface{}{"it"})
+}
+
+func TestSubqueryOrderBy(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	sub := NewSQLServerSelect(dept, false).SetFields("id").SetFilter("name", "=", "it")
+	b := NewSQLServerSelect(emp, false).SetFilter("deptid", "IN", sub)
+	wantQ := "SELECT * FROM [emp] WHERE [deptid] IN (SELECT [id] FROM [dept] WHERE [name] = @p1) ORDER BY [id]"
+	testQuery(t, b, wantQ, []interface{}{"it"})
+
+	//ORDER BY is kept when the subquery is limited.
+	sub.OrderBy(Desc("name")).SetLimit(3)
+	b = NewSQLServerSelect(SubQuery(sub, "t"), false)
+	wantQ = "SELECT * FROM (SELECT TOP 3 [id] FROM [dept] WHERE [name] = @p1 ORDER BY [name] DESC,[id]) AS [t] ORDER BY [id]"
+	testQuery(t, b, wantQ, []interface{}{"it"})
+
+	with := NewSQLServerSelect(emp, false).SetFields("id", "deptid")
+	b = NewSQLServerSelect(CTE("e", with), false).With("e", with)
+	wantQ = "WITH [e] AS (SELECT [id],[deptid] FROM [emp]) SELECT * FROM [e] ORDER BY [id]"
+	testQuery(t, b, wantQ, nil)
+}
+
+func TestSubqueryCursor(t *testing.T) {
+	emp, dept := newSubqueryTables(t)
+	sub := NewPQSelect(dept, false).SetFields("id")
+	b := NewPQSelect(emp, false).SetFilter("name", "=", "al").SetFilter("deptid", "IN", sub).SetLimit(2)
+	cursor := b.Cursor()
+	b.Reset()
+	b.SetFilter("deptid", "IN", sub)
+	if err := b.setCursor(cursor); err != nil {
+		t.Fatal(err)
+	}
+	wantQ := "SELECT * FROM emp WHERE name = $1 AND deptid IN (SELECT id FROM dept) ORDER BY id LIMIT 2"
+	testQuery(t, b, wantQ, []interface{}{"al"})
+}
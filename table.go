@@ -15,6 +15,10 @@ type Table struct {
 	//fieldsIndex is an index of the field on the struct.
 	fieldsIndex []int
 	primaryKey  []string
+	//typ is the struct type of the table, the fieldsIndex is only valid for this type.
+	typ reflect.Type
+	//defaults is the fields tagged with qb:"default" or qb:"omitempty".
+	defaults []string
 }
 
 //NewTaable create Tabler implementation using reflection.
@@ -36,6 +40,7 @@ func fromStruct(name string, s reflect.Type) (t Table, err error) {
 	}
 
 	t.name = strings.ToLower(name)
+	t.typ = s
 	n := s.NumField()
 	if n <= 0 {
 		return t, errors.New("struct doesn't have a field.")
@@ -48,11 +53,25 @@ func fromStruct(name string, s reflect.Type) (t Table, err error) {
 		}
 		t.fields = append(t.fields, strings.ToLower(field.Name))
 		t.fieldsIndex = append(t.fieldsIndex, i)
+		if isDefaultTag(field.Tag.Get("qb")) {
+			t.defaults = append(t.defaults, strings.ToLower(field.Name))
+		}
 	}
 	t.primaryKey, err = primaryKeys(s)
 	return t, err
 }
 
+//isDefaultTag report whether the qb tag mark the field to be omitted from the partial insert when zero.
+func isDefaultTag(tag string) bool {
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "default", "omitempty":
+			return true
+		}
+	}
+	return false
+}
+
 func isExported(field reflect.StructField) bool {
 	return field.PkgPath == ""
 }
@@ -103,3 +122,8 @@ func (t Table) Fields() []string {
 func (t Table) PrimaryKeys() []string {
 	return t.primaryKey
 }
+
+//DefaultFields return the fields that have the default value on the database.
+func (t Table) DefaultFields() []string {
+	return t.defaults
+}
@@ -114,6 +114,22 @@ func TestUseStructName(t *testing.T) {
 	}
 }
 
+func TestDefaultFields(t *testing.T) {
+	type item struct {
+		ID        int64 `pk:"1" qb:"default"`
+		Name      string
+		CreatedAt time.Time `json:"created_at" qb:" default"`
+	}
+	st, err := NewTable("", item{})
+	if err != nil {
+		t.Fatalf("create table err:%v", err)
+	}
+	want := []string{"id", "createdat"}
+	if got := st.DefaultFields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got = %v want %v", got, want)
+	}
+}
+
 func TestNewTableShouldFail(t *testing.T) {
 	type conflictPK struct {
 		DocNo string `pk:"1"`
@@ -21,23 +21,59 @@ type fieldValue struct {
 
 //Update use track updated field and to construct the update query.
 type Update struct {
-	t       Tabler
-	driver  string
-	updated []fieldValue
-	filters []filter
+	t        Tabler
+	d        Dialect
+	updated  []fieldValue
+	filters  []filter
+	progress func(inserted, total int)
+	//conflict and conflictUpdate is the fields used by Upsert.
+	conflict       []string
+	conflictUpdate []string
+	doNothing      bool
+}
+
+//NewUpdate return an update for the database that use the dialect d.
+func NewUpdate(d Dialect, t Tabler) *Update {
+	return &Update{t: t, d: d}
 }
 
 //NewPQUpdate return and update to use
 func NewPQUpdate(t Tabler) *Update {
-	return &Update{t: t, driver: "pq"}
+	return NewUpdate(PQ{}, t)
+}
+
+//NewMySQLUpdate return an update for MySQL and MariaDB database.
+func NewMySQLUpdate(t Tabler) *Update {
+	return NewUpdate(MySQL{}, t)
+}
+
+//NewSQLiteUpdate return an update for SQLite database.
+func NewSQLiteUpdate(t Tabler) *Update {
+	return NewUpdate(SQLite{}, t)
+}
+
+//NewSQLServerUpdate return an update for Microsoft SQL Server database.
+func NewSQLServerUpdate(t Tabler) *Update {
+	return NewUpdate(SQLServer{}, t)
 }
 
 //Set set the field to be updated, the field to be update can't be part of Primary keys.
+//The value can be Col to copy the other field, or Expr such as Increment, Decrement and Func
+//to compute the value on the database.
 func (u *Update) Set(field string, value interface{}) error {
 	field = strings.ToLower(field)
 	if !isFieldExist(u.t, field) {
 		return fmt.Errorf("field %s doesn't exist on table %s", field, u.t.TableName())
 	}
+	if col, ok := value.(Col); ok {
+		value = Col(strings.ToLower(string(col)))
+		if !isFieldExist(u.t, string(value.(Col))) {
+			return fmt.Errorf("field %s doesn't exist on table %s", col, u.t.TableName())
+		}
+	}
+	if e, ok := value.(Expr); ok && e.placeholders() != len(e.args) {
+		return fmt.Errorf("expression of field %s has %d placeholders but %d args", field, e.placeholders(), len(e.args))
+	}
 	if isPK(u.t, field) {
 		return fmt.Errorf("field %s is pimary key", field)
 	}
@@ -59,17 +95,24 @@ func (u *Update) set(field string, value interface{}) {
 
 //SetFilter set filter for the query, multiple filter will be AND together.
 func (u *Update) SetFilter(field, op string, value interface{}) *Update {
-	f := filter{
-		field: strings.ToLower(field),
-		op:    op,
-		value: value,
+	u.filters = append(u.filters, newFilter(field, op, value))
+	return u
+}
+
+//Where set the conditions for the query, conditions will be AND with other filter.
+func (u *Update) Where(conds ...Condition) *Update {
+	for _, cond := range conds {
+		u.filters = append(u.filters, asFilter(cond))
 	}
-	u.filters = append(u.filters, f)
 	return u
 }
 
 //Update update data on the database where the value is come from call to Set method.
 func (u *Update) Update(dbe DBExecer) error {
+	if err := filtersOpError(u.filters); err != nil {
+		u.reset()
+		return err
+	}
 	q, args := u.UpdateQuery()
 	_, err := dbe.Exec(q, args...)
 	return err
@@ -87,7 +130,7 @@ func (u *Update) UpdateQuery() (query string, args []interface{}) {
 	if len(argsW) > 0 {
 		args = append(args, argsW...)
 	}
-	query = "UPDATE " + u.t.TableName() + query + where
+	query = "UPDATE " + u.d.Quote(u.t.TableName()) + query + where
 	u.reset()
 	return
 }
@@ -108,39 +151,64 @@ func (u *Update) UpdateByPKQuery() (query string, args []interface{}) {
 	next := 1
 	query, args, next = u.updateSetQuery(next)
 	w, _ := u.pkWhereQuery(next)
-	query = "UPDATE " + u.t.TableName() + query + w
+	query = "UPDATE " + u.d.Quote(u.t.TableName()) + query + w
 	u.reset()
 	return query, args
 }
 
-//Insert insert data to database where the value is come from src.
+//Insert insert data to database where the value is come from src,
+//src must be struct, pointer to struct or implement ValueArger.
 func (u *Update) Insert(dbe DBExecer, src interface{}) error {
-	args := u.getArgs(src)
-	_, err := dbe.Exec(u.InsertQuery(), args...)
+	args, err := u.getArgs(src)
+	if err != nil {
+		return err
+	}
+	_, err = dbe.Exec(u.InsertQuery(), args...)
 	return err
 }
 
 //InsertQuery return a query to insert to the database.
 func (u *Update) InsertQuery() string {
-	n := len(u.t.Fields())
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		u.t.TableName(),
-		strings.Join(u.t.Fields(), ","),
-		u.makePlaceholder(n))
-	return query
+	return u.d.Insert(u.t.TableName(), u.t.Fields(), 1, nil)
 }
 
-func (u *Update) getArgs(src interface{}) []interface{} {
-	args := make([]interface{}, len(u.t.Fields()))
-	if tbl, ok := u.t.(Table); ok {
-		rt := reflect.ValueOf(src)
+//ValueArger is an interface that use to get the values of the fields when insert the data,
+//it is the counterpart of ScanArger.
+type ValueArger interface {
+	ValueArgs(fields []string) []interface{}
+}
+
+//getArgs return the values of the table fields from src,
+//src must be struct, pointer to struct or implement ValueArger.
+func (u *Update) getArgs(src interface{}) ([]interface{}, error) {
+	fields := u.t.Fields()
+	if va, ok := src.(ValueArger); ok {
+		args := va.ValueArgs(fields)
+		if len(args) != len(fields) {
+			return nil, fmt.Errorf("ValueArgs return %d values for %d fields", len(args), len(fields))
+		}
+		return args, nil
+	}
+	rv := reflect.Indirect(reflect.ValueOf(src))
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("src must be struct, pointer to struct or implement ValueArger")
+	}
+	args := make([]interface{}, len(fields))
+	if tbl, ok := u.t.(Table); ok && tbl.typ == rv.Type() {
 		for i, idx := range tbl.fieldsIndex {
-			args[i] = rt.Field(idx).Interface()
+			args[i] = rv.Field(idx).Interface()
+		}
+		return args, nil
+	}
+	//the field of other Tabler or other struct is matched with the struct field by name.
+	for i, field := range fields {
+		v := structField(rv, field)
+		if !v.IsValid() {
+			return nil, fmt.Errorf("field %s doesn't exist on %s", field, rv.Type())
 		}
-		return args
+		args[i] = v.Interface()
 	}
-	//TODO:use reflection
-	return args
+	return args, nil
 }
 
 //DeleteByPK delete the data from database that match with the arrgs.
@@ -155,12 +223,16 @@ func (u *Update) DeleteByPK(dbe DBExecer, args ...interface{}) error {
 //DeleteByPKQuery return a delete query with the where clause is set by the table Primary Keys.
 func (u *Update) DeleteByPKQuery() string {
 	w, _ := u.pkWhereQuery(1)
-	query := "DELETE FROM " + u.t.TableName() + w
+	query := "DELETE FROM " + u.d.Quote(u.t.TableName()) + w
 	return query
 }
 
 //Delete delete the data from the database that match with DeleteQuery.
 func (u *Update) Delete(dbe DBExecer) error {
+	if err := filtersOpError(u.filters); err != nil {
+		u.reset()
+		return err
+	}
 	query, args := u.DeleteQuery()
 	_, err := dbe.Exec(query, args...)
 	return err
@@ -169,31 +241,18 @@ func (u *Update) Delete(dbe DBExecer) error {
 //DeleteQuery return a query to delete data on the database that match the filter.
 func (u *Update) DeleteQuery() (query string, args []interface{}) {
 	query, args, _ = u.filterQuery(1)
-	query = "DELETE FROM " + u.t.TableName() + query
+	query = "DELETE FROM " + u.d.Quote(u.t.TableName()) + query
 	u.reset()
 	return query, args
 }
 
-func (u *Update) makePlaceholder(n int) string {
-	if u.driver == "pq" {
-		return pqMakePlaceholder(n)
-	}
-	return "makePlaceholder: should not happen"
-}
-
 //starting is placeholder starting number..
 func (u *Update) updateSetQuery(starting int) (query string, args []interface{}, next int) {
-	if u.driver == "pq" {
-		return pqUpdateSet(u.updated, starting)
-	}
-	return
+	return updateSetQuery(u.d, u.updated, starting)
 }
 
 func (u *Update) filterQuery(starting int) (where string, args []interface{}, next int) {
-	if u.driver == "pq" {
-		return pqFilter(u.filters, starting)
-	}
-	return
+	return filterQuery(u.d, u.filters, starting)
 }
 
 func (u *Update) reset() {
@@ -220,8 +279,5 @@ func isPK(t Tabler, field string) bool {
 }
 
 func (u *Update) pkWhereQuery(starting int) (string, int) {
-	if u.driver == "pq" {
-		return pqWhere(u.t.PrimaryKeys(), starting)
-	}
-	return "pkWhereQuery: unreacheable", 0
+	return whereQuery(u.d, u.t.PrimaryKeys(), starting)
 }
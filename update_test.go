@@ -21,6 +21,12 @@ func TestUpdateQuery(t *testing.T) {
 	wantQ = "UPDATE emp SET name = $1 WHERE id = $2"
 	wantA = []interface{}{"al", "i8"}
 	testUpdateQuery(t, b, wantQ, wantA)
+	b.Set("name", "al")
+	b.Set("age", 20)
+	b.SetFilter("id", "=", "i8")
+	wantQ = "UPDATE emp SET name = $1,age = $2 WHERE id = $3"
+	wantA = []interface{}{"al", 20, "i8"}
+	testUpdateQuery(t, b, wantQ, wantA)
 }
 
 func TestInsertQuery(t *testing.T) {
@@ -82,3 +88,129 @@ func newUpdateBuilder(t *testing.T, tbl interface{}) *Update {
 	b := NewPQUpdate(ti)
 	return b
 }
+
+func TestUpdateSetExpression(t *testing.T) {
+	type Emp struct {
+		ID        string `pk:"1"`
+		Name      string
+		Nickname  string
+		Child     int
+		Age       int
+		UpdatedAt string
+	}
+	b := newUpdateBuilder(t, Emp{})
+	b.Set("child", Increment(1))
+	b.Set("age", Decrement(2))
+	b.Set("nickname", Col("Name"))
+	b.Set("updatedat", Func("now"))
+	b.Set("name", Func("upper", "al"))
+	wantQ := "UPDATE emp SET child = child + $1,age = age - $2,nickname = name,updatedat = now(),name = upper($3)" +
+		" WHERE id = $4"
+	gotQ, gotA := b.UpdateByPKQuery()
+	if gotQ != wantQ {
+		t.Errorf("got: %s\n        want %s", gotQ, wantQ)
+	}
+	if !reflect.DeepEqual(gotA, []interface{}{1, 2, "al"}) {
+		t.Errorf("got: %v want [1 2 al]", gotA)
+	}
+
+	if err := b.Set("name", Col("notexist")); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+	for _, e := range []Expr{NewExpr("upper(?)"), NewExpr("concat(?, ?)", "a"), NewExpr("name ?? 'x'", 1)} {
+		if err := b.Set("name", e); err == nil {
+			t.Errorf("got: nil want an error for %s", e.query)
+		}
+	}
+	if err := b.Set("name", NewExpr("name || ? || '??'", "x")); err != nil {
+		t.Errorf("got err: %v want nil", err)
+	}
+}
+
+type empTabler struct{}
+
+func (empTabler) TableName() string     { return "emp" }
+func (empTabler) Fields() []string      { return []string{"id", "name", "age"} }
+func (empTabler) PrimaryKeys() []string { return []string{"id"} }
+
+type empValueArger struct {
+	id   string
+	name string
+}
+
+func (e empValueArger) ValueArgs(fields []string) []interface{} {
+	args := make([]interface{}, len(fields))
+	for i, field := range fields {
+		switch field {
+		case "id":
+			args[i] = e.id
+		case "name":
+			args[i] = e.name
+		}
+	}
+	return args
+}
+
+//countingArger return one value more on every call.
+type countingArger struct {
+	calls *int
+}
+
+func (c countingArger) ValueArgs(fields []string) []interface{} {
+	*c.calls++
+	return make([]interface{}, len(fields)+*c.calls-1)
+}
+
+func TestInsertArgs(t *testing.T) {
+	type Emp struct {
+		Age  int
+		Name string
+		ID   string
+	}
+	type Person struct {
+		ID   string `pk:"1"`
+		Name string
+	}
+	tests := []struct {
+		t    Tabler
+		src  interface{}
+		want []interface{}
+	}{
+		{empTabler{}, Emp{20, "al", "A1"}, []interface{}{"A1", "al", 20}},
+		{empTabler{}, &Emp{21, "bo", "B2"}, []interface{}{"B2", "bo", 21}},
+		{empTabler{}, empValueArger{"C3", "cy"}, []interface{}{"C3", "cy", nil}},
+		{empTabler{}, countingArger{new(int)}, []interface{}{nil, nil, nil}},
+	}
+	//the struct that is not the struct of the Table is matched by name.
+	tbl, err := NewTable("emp", Person{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests = append(tests, struct {
+		t    Tabler
+		src  interface{}
+		want []interface{}
+	}{tbl, Emp{22, "cy", "D4"}, []interface{}{"D4", "cy"}})
+	for _, test := range tests {
+		got, err := NewPQUpdate(test.t).getArgs(test.src)
+		if err != nil {
+			t.Errorf("got err: %v want nil", err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("got: %v want %v", got, test.want)
+		}
+	}
+	u := NewPQUpdate(empTabler{})
+	for _, src := range []interface{}{Person{"A1", "al"}, "A1", nil, (*Emp)(nil)} {
+		if _, err := u.getArgs(src); err == nil {
+			t.Errorf("got: nil want an error for %v", src)
+		}
+	}
+	calls := 1
+	if _, err := u.getArgs(countingArger{&calls}); err == nil {
+		t.Errorf("got: nil want an error for the wrong number of values")
+	}
+	if _, err := NewPQUpdate(tbl).getArgs(struct{ ID int }{1}); err == nil {
+		t.Errorf("got: nil want an error for the missing field")
+	}
+}
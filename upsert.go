@@ -0,0 +1,96 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+)
+
+//OnConflict set the fields to find the conflicting row for Upsert, by default the primary keys is used.
+//MySQL always use the primary and unique keys of the table.
+func (u *Update) OnConflict(fieldName ...string) *Update {
+	u.conflict = u.conflict[:0]
+	for _, field := range fieldName {
+		u.conflict = append(u.conflict, strings.ToLower(field))
+	}
+	return u
+}
+
+//DoUpdate set the fields to update when the row is conflicting, by default all the fields
+//except the conflict fields is updated.
+func (u *Update) DoUpdate(fieldName ...string) *Update {
+	u.doNothing = false
+	u.conflictUpdate = u.conflictUpdate[:0]
+	for _, field := range fieldName {
+		u.conflictUpdate = append(u.conflictUpdate, strings.ToLower(field))
+	}
+	return u
+}
+
+//DoNothing keep the conflicting row as it is.
+func (u *Update) DoNothing() *Update {
+	u.doNothing = true
+	u.conflictUpdate = u.conflictUpdate[:0]
+	return u
+}
+
+//Upsert insert src to the database, or update the row that conflict with src,
+//see OnConflict, DoUpdate and DoNothing.
+func (u *Update) Upsert(dbe DBExecer, src interface{}) error {
+	if err := u.upsertError(); err != nil {
+		return err
+	}
+	args, err := u.getArgs(src)
+	if err != nil {
+		return err
+	}
+	_, err = dbe.Exec(u.UpsertQuery(), args...)
+	return err
+}
+
+//UpsertQuery return a query to insert or update the row, the args is the same as InsertQuery.
+func (u *Update) UpsertQuery() string {
+	conflict, update := u.upsertFields()
+	return u.d.Upsert(u.t.TableName(), u.t.Fields(), 1, conflict, update)
+}
+
+//upsertFields return the conflict fields and the fields to update.
+func (u *Update) upsertFields() (conflict, update []string) {
+	conflict = u.conflict
+	if len(conflict) == 0 {
+		conflict = u.t.PrimaryKeys()
+	}
+	if u.doNothing {
+		return conflict, nil
+	}
+	if len(u.conflictUpdate) != 0 {
+		return conflict, u.conflictUpdate
+	}
+	for _, field := range u.t.Fields() {
+		if !isIn(field, conflict) {
+			update = append(update, field)
+		}
+	}
+	return conflict, update
+}
+
+func (u *Update) upsertError() error {
+	conflict, update := u.upsertFields()
+	if len(conflict) == 0 {
+		return fmt.Errorf("table %s doesn't have the conflict fields", u.t.TableName())
+	}
+	for _, field := range append(append([]string{}, conflict...), update...) {
+		if !isFieldExist(u.t, field) {
+			return fmt.Errorf("field %s doesn't exist on table %s", field, u.t.TableName())
+		}
+	}
+	return nil
+}
+
+func isIn(s string, ss []string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package qb
+
+import "testing"
+
+func TestUpsertQuery(t *testing.T) {
+	type Emp struct {
+		ID   string `pk:"1"`
+		Code string
+		Name string
+		Age  int
+	}
+	tbl, err := NewTable("", Emp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		u    *Update
+		want string
+	}{
+		{NewPQUpdate(tbl),
+			"INSERT INTO emp (id,code,name,age) VALUES ($1,$2,$3,$4) ON CONFLICT (id) DO UPDATE SET code = EXCLUDED.code,name = EXCLUDED.name,age = EXCLUDED.age"},
+		{NewPQUpdate(tbl).OnConflict("Code").DoUpdate("name"),
+			"INSERT INTO emp (id,code,name,age) VALUES ($1,$2,$3,$4) ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name"},
+		{NewSQLiteUpdate(tbl).DoNothing(),
+			`INSERT INTO "emp" ("id","code","name","age") VALUES (?,?,?,?) ON CONFLICT ("id") DO NOTHING`},
+		{NewMySQLUpdate(tbl).DoUpdate("name", "age"),
+			"INSERT INTO `emp` (`id`,`code`,`name`,`age`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`),`age` = VALUES(`age`)"},
+		{NewMySQLUpdate(tbl).DoNothing(),
+			"INSERT INTO `emp` (`id`,`code`,`name`,`age`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `id` = `id`"},
+		{NewUpdate(SQLServer{}, tbl).DoUpdate("name"),
+			"MERGE INTO [emp] AS target USING (VALUES (@p1,@p2,@p3,@p4)) AS source ([id],[code],[name],[age]) ON target.[id] = source.[id]" +
+				" WHEN MATCHED THEN UPDATE SET [name] = source.[name]" +
+				" WHEN NOT MATCHED THEN INSERT ([id],[code],[name],[age]) VALUES (source.[id],source.[code],source.[name],source.[age]);"},
+	}
+	for _, test := range tests {
+		if err := test.u.upsertError(); err != nil {
+			t.Errorf("got err: %v want nil", err)
+		}
+		if got := test.u.UpsertQuery(); got != test.want {
+			t.Errorf("got: %s\n        want %s", got, test.want)
+		}
+	}
+
+	if err := NewPQUpdate(tbl).DoUpdate("notexist").upsertError(); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+	if err := NewPQUpdate(tbl).OnConflict("notexist").upsertError(); err == nil {
+		t.Errorf("got: nil want an error")
+	}
+}